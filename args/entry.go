@@ -170,3 +170,22 @@ func GetFlagValue[T any](name string) (T, error) {
 
 	return blank, fmt.Errorf("flag %s is not of type %T", name, blank)
 }
+
+// GetSetFlags returns the flags given on the command line by name, with their
+// values as text. Flags left at their defaults are not included.
+func GetSetFlags() (map[string]string, error) {
+	if transformed == nil {
+		return nil, wrap(fmt.Errorf("get set flags called before flag parse"))
+	}
+
+	set := map[string]string{}
+	flag.Visit(func(f *flag.Flag) {
+		for _, e := range entries {
+			if e.Short() == f.Name {
+				set[e.Name()] = f.Value.String()
+			}
+		}
+	})
+
+	return set, nil
+}
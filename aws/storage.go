@@ -4,11 +4,14 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/lspaccatrosi16/go-cli-tools/progress"
 )
 
 type Bucket struct {
@@ -20,10 +23,23 @@ type Bucket struct {
 const partMibs int64 = 10
 
 func (b *Bucket) UploadFile(key string, file []byte) error {
-	buf := bytes.NewReader(file)
+	return b.upload(key, bytes.NewReader(file))
+}
+
+func (b *Bucket) UploadFileWithProgress(key string, file []byte) error {
+	bar := progress.NewBar(key, int64(len(file)), progress.Bytes)
+	defer bar.Finish()
+
+	// the uploader reads each part of a section reader while sending it, but
+	// would read it once beforehand to sign it unless the payload is unsigned
+	body := bar.SectionReader(bytes.NewReader(file), int64(len(file)))
+	return b.upload(key, body, s3.WithAPIOptions(v4.SwapComputePayloadSHA256ForUnsignedPayloadMiddleware))
+}
 
+func (b *Bucket) upload(key string, buf io.Reader, clientOptions ...func(*s3.Options)) error {
 	uploader := manager.NewUploader(b.S3Client, func(u *manager.Uploader) {
 		u.PartSize = partMibs * 1024 * 1024
+		u.ClientOptions = append(u.ClientOptions, clientOptions...)
 	})
 
 	_, err := uploader.Upload(context.TODO(), &s3.PutObjectInput{
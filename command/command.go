@@ -6,11 +6,13 @@ import (
 	"sort"
 
 	"github.com/lspaccatrosi16/go-cli-tools/input"
+	"github.com/lspaccatrosi16/go-cli-tools/output"
 )
 
 type cmd struct {
 	Name        string
 	Description string
+	Help        string
 	Exec        *func() error
 }
 
@@ -90,6 +92,18 @@ func (m *Manager) Register(name string, description string, exec func() error) {
 	m.cmds = append(m.cmds, &newcmd)
 }
 
+// RegisterWithHelp registers a command whose help text is shown underneath
+// the list while it is highlighted in the TUI.
+func (m *Manager) RegisterWithHelp(name string, description string, help string, exec func() error) {
+	newcmd := cmd{
+		Name:        name,
+		Description: description,
+		Help:        help,
+		Exec:        &exec,
+	}
+	m.cmds = append(m.cmds, &newcmd)
+}
+
 func (m *Manager) RegisterData(name string, description string, exec func() (any, error)) {
 	newcmd := datacmd{
 		Name:        name,
@@ -99,6 +113,16 @@ func (m *Manager) RegisterData(name string, description string, exec func() (any
 	m.datacmds = append(m.datacmds, &newcmd)
 }
 
+func (m *Manager) RegisterTable(name string, description string, exec func() (*output.Table, error)) {
+	m.Register(name, description, func() error {
+		table, err := exec()
+		if err != nil {
+			return err
+		}
+		return table.Page()
+	})
+}
+
 func (m *Manager) Run(str string) {
 	for _, cmd := range m.cmds {
 		if cmd.Name == str {
@@ -114,6 +138,21 @@ func (m *Manager) Run(str string) {
 	m.Help()
 }
 
+// RunWithError is Run for scripts: the error from the command is returned
+// rather than printed, so the caller can exit with a non-zero status.
+func (m *Manager) RunWithError(str string) error {
+	for _, cmd := range m.cmds {
+		if cmd.Name == str {
+			return cmd.Run()
+		}
+	}
+
+	fmt.Printf("command \"%s\" was not found\n", str)
+	m.Help()
+
+	return fmt.Errorf("command \"%s\" was not found", str)
+}
+
 func (m *Manager) RunData(str string) (any, error) {
 	for _, cmd := range m.datacmds {
 		if cmd.Name == str {
@@ -148,7 +187,7 @@ func (m *Manager) DataTui() (any, error) {
 		descriptions = append(descriptions, cmd.Description)
 	}
 
-	selected := m.runTui(names, descriptions, maxCmdLen)
+	selected := m.runTui(names, descriptions, make([]string, len(names)), maxCmdLen)
 
 	if selected == "exit" {
 		return nil, fmt.Errorf("no value selected")
@@ -161,6 +200,7 @@ func (m *Manager) Tui() bool {
 	maxCmdLen := 0
 	names := []string{}
 	descriptions := []string{}
+	helps := []string{}
 
 	for _, cmd := range m.cmds {
 		if len(cmd.Name) > maxCmdLen {
@@ -168,9 +208,10 @@ func (m *Manager) Tui() bool {
 		}
 		names = append(names, cmd.Name)
 		descriptions = append(descriptions, cmd.Description)
+		helps = append(helps, cmd.Help)
 	}
 
-	selected := m.runTui(names, descriptions, maxCmdLen)
+	selected := m.runTui(names, descriptions, helps, maxCmdLen)
 
 	if selected == "exit" {
 		return true
@@ -180,13 +221,13 @@ func (m *Manager) Tui() bool {
 	return false
 }
 
-func (m *Manager) runTui(names []string, descriptions []string, maxCmdLen int) string {
+func (m *Manager) runTui(names []string, descriptions []string, helps []string, maxCmdLen int) string {
 	options := optList{}
 
 	for i := 0; i < len(names); i++ {
 		name := names[i]
 		description := descriptions[i]
-		options = append(options, input.SelectOption{Name: fmt.Sprintf("%-*s : %s", maxCmdLen, name, description), Value: name})
+		options = append(options, input.SelectOption{Name: fmt.Sprintf("%-*s : %s", maxCmdLen, name, description), Value: name, Description: helps[i]})
 	}
 
 	sort.Sort(options)
@@ -0,0 +1,34 @@
+package command
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRunWithError(t *testing.T) {
+	failure := errors.New("failed")
+
+	m := NewManager(ManagerConfig{})
+	m.Register("ok", "succeeds", func() error { return nil })
+	m.Register("fail", "fails", func() error { return failure })
+
+	tests := []struct {
+		name string
+		want error
+		fail bool
+	}{
+		{"ok", nil, false},
+		{"fail", failure, true},
+		{"missing", nil, true},
+	}
+
+	for _, test := range tests {
+		err := m.RunWithError(test.name)
+		if (err != nil) != test.fail {
+			t.Fatalf("%s: got %v", test.name, err)
+		}
+		if test.want != nil && !errors.Is(err, test.want) {
+			t.Fatalf("%s: got %v, want %v", test.name, err, test.want)
+		}
+	}
+}
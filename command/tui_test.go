@@ -0,0 +1,48 @@
+//go:build !windows
+
+package command
+
+import (
+	"testing"
+
+	"github.com/lspaccatrosi16/go-cli-tools/termtest"
+)
+
+func TestTui(t *testing.T) {
+	ran := ""
+
+	m := NewManager(ManagerConfig{})
+	m.Register("list", "lists things", func() error { ran = "list"; return nil })
+	m.Register("greet", "says hello", func() error { ran = "greet"; return nil })
+
+	s := termtest.New(t, termtest.Options{})
+
+	var exit bool
+	s.Run(func() {
+		exit = m.Tui()
+	})
+
+	s.Expect("Select the command to execute")
+	s.Expect("list  : lists things")
+	s.Press("down", "enter")
+	s.Wait()
+
+	if exit {
+		t.Error("Tui reported exit after running a command")
+	}
+	if ran != "greet" {
+		t.Errorf("ran %q, want greet", ran)
+	}
+
+	s.Run(func() {
+		exit = m.Tui()
+	})
+
+	s.Expect("Back")
+	s.Press("enter")
+	s.Wait()
+
+	if !exit {
+		t.Error("Tui did not report exit after Back")
+	}
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// WriteFileAtomic replaces path with data so that readers only ever see the
+// old or the new contents. The data is written to a temporary file beside
+// path, synced and renamed over it. The previous contents are kept in
+// path+".bak" and the permissions of an existing file are kept; new files get
+// perm.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	return WriteFileAtomicWithConfig(path, data, AtomicConfig{Perm: perm})
+}
+
+// AtomicConfig holds optional settings for writing a file atomically.
+type AtomicConfig struct {
+	// Perm is used for new files, 0644 when zero.
+	Perm os.FileMode
+	// ForcePerm applies Perm to existing files and their backup as well, e.g.
+	// so that files holding secrets are never left readable by others.
+	ForcePerm bool
+}
+
+// WriteFileAtomicWithConfig is WriteFileAtomic with extra settings.
+func WriteFileAtomicWithConfig(path string, data []byte, options AtomicConfig) error {
+	perm := options.Perm
+	if perm == 0 {
+		perm = 0o644
+	}
+
+	if info, err := os.Stat(path); err == nil {
+		if !options.ForcePerm {
+			perm = info.Mode().Perm()
+		}
+
+		previous, err := os.ReadFile(path)
+		if err != nil {
+			return wrap(err)
+		}
+		if err := replaceFile(path+".bak", previous, perm); err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return wrap(err)
+	}
+
+	return replaceFile(path, data, perm)
+}
+
+func replaceFile(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return wrap(err)
+	}
+	// harmless once the rename has happened
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return wrap(err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return wrap(err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return wrap(err)
+	}
+	if err := tmp.Close(); err != nil {
+		return wrap(err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return wrap(err)
+	}
+
+	return syncDir(dir)
+}
+
+// LockFile takes an advisory lock for path, waiting until no other process
+// holds it, and returns the function that releases it. The lock is held on
+// path+".lock" so that it survives path being replaced.
+func LockFile(path string) (func() error, error) {
+	fh, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, wrap(err)
+	}
+
+	if err := lockFile(fh); err != nil {
+		fh.Close()
+		return nil, wrap(err)
+	}
+
+	return func() error {
+		err := unlockFile(fh)
+		if closeErr := fh.Close(); err == nil {
+			err = closeErr
+		}
+		return wrap(err)
+	}, nil
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestWriteFileAtomicPerm(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing os.FileMode
+		options  AtomicConfig
+		want     os.FileMode
+	}{
+		{"new file", 0, AtomicConfig{Perm: 0o600}, 0o600},
+		{"new file default", 0, AtomicConfig{}, 0o644},
+		{"keeps existing", 0o640, AtomicConfig{Perm: 0o600}, 0o640},
+		{"forced", 0o644, AtomicConfig{Perm: 0o600, ForcePerm: true}, 0o600},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "file")
+			if test.existing != 0 {
+				if err := os.WriteFile(path, []byte("old"), test.existing); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.Chmod(path, test.existing); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := WriteFileAtomicWithConfig(path, []byte("new"), test.options); err != nil {
+				t.Fatal(err)
+			}
+
+			files := []string{path}
+			if test.existing != 0 {
+				files = append(files, path+".bak")
+			}
+			for _, file := range files {
+				info, err := os.Stat(file)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if info.Mode().Perm() != test.want {
+					t.Fatalf("%s has mode %o, want %o", filepath.Base(file), info.Mode().Perm(), test.want)
+				}
+			}
+
+			if test.existing != 0 {
+				if old, _ := os.ReadFile(path + ".bak"); string(old) != "old" {
+					t.Fatalf("backup holds %q", old)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateConfigFileConcurrent(t *testing.T) {
+	type counter struct{ Count int }
+	path := filepath.Join(t.TempDir(), "c.json")
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := UpdateConfigFile(path, []byte(`{}`), func(c *counter) error {
+				c.Count++
+				return nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	c, err := ReadConfigFile[counter](path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Count != 20 {
+		t.Fatalf("got %d updates, want 20", c.Count)
+	}
+}
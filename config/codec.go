@@ -0,0 +1,516 @@
+package config
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/ini.v1"
+	"gopkg.in/yaml.v3"
+)
+
+// Codec converts a config value to and from the contents of a file.
+type Codec interface {
+	Encode(v any) ([]byte, error)
+	Decode(data []byte, v any) error
+}
+
+// The YAML, TOML and INI codecs go through JSON, so field names come from json
+// tags and values are written the way encoding/json writes them in every
+// format. INI files hold one level of sections, named with dots when nested,
+// and slices as comma separated lists.
+var (
+	JSON Codec = jsonCodec{}
+	YAML Codec = docCodec{parse: parseYAML, format: formatYAML}
+	TOML Codec = docCodec{parse: parseTOML, format: formatTOML}
+	INI  Codec = docCodec{parse: parseINI, format: formatINI, untyped: true}
+)
+
+var codecs = map[string]Codec{
+	".json": JSON,
+	".yaml": YAML,
+	".yml":  YAML,
+	".toml": TOML,
+	".ini":  INI,
+}
+
+// RegisterCodec makes CodecFor use codec for files ending in extension, e.g.
+// ".hcl".
+func RegisterCodec(extension string, codec Codec) {
+	codecs[strings.ToLower(extension)] = codec
+}
+
+// CodecFor picks the codec for path by its extension. Unknown extensions are
+// read and written as JSON.
+func CodecFor(path string) Codec {
+	if codec, ok := codecs[strings.ToLower(filepath.Ext(path))]; ok {
+		return codec
+	}
+	return JSON
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Encode(v any) ([]byte, error) {
+	buf := bytes.NewBuffer([]byte{})
+	encoder := json.NewEncoder(buf)
+
+	encoder.SetIndent("", "\t")
+
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (jsonCodec) Decode(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	for {
+		err := decoder.Decode(v)
+
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// docCodec adapts a format that reads and writes plain maps and slices.
+type docCodec struct {
+	parse  func(data []byte) (any, error)
+	format func(doc map[string]any) ([]byte, error)
+	// untyped formats store every value as text, which is converted using
+	// the type being decoded into
+	untyped bool
+}
+
+func (c docCodec) Encode(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	root, ok := normalize(doc).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("only objects can be stored, found %T", v)
+	}
+
+	return c.format(root)
+}
+
+func (c docCodec) Decode(data []byte, v any) error {
+	converted, err := c.toJSON(data, reflect.TypeOf(v))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(converted, v)
+}
+
+func (c docCodec) toJSON(data []byte, t reflect.Type) ([]byte, error) {
+	doc, err := c.parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	doc = normalize(doc)
+	if c.untyped {
+		doc = coerce(doc, t)
+	}
+
+	return json.Marshal(doc)
+}
+
+// toJSON converts the contents of a file to JSON, e.g. to check it against a
+// schema. t is the type the file will be decoded into.
+func toJSON(codec Codec, data []byte, t reflect.Type) ([]byte, error) {
+	switch c := codec.(type) {
+	case jsonCodec:
+		return data, nil
+	case docCodec:
+		return c.toJSON(data, t)
+	default:
+		v := reflect.New(t)
+		if err := codec.Decode(data, v.Interface()); err != nil {
+			return nil, err
+		}
+		return json.Marshal(v.Interface())
+	}
+}
+
+// normalize turns what the format libraries decode into what encoding/json
+// would: string keys, no nils inside objects and plain numbers.
+func normalize(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		out := map[string]any{}
+		for key, elem := range val {
+			if elem != nil {
+				out[key] = normalize(elem)
+			}
+		}
+		return out
+	case map[any]any:
+		out := map[string]any{}
+		for key, elem := range val {
+			if elem != nil {
+				out[fmt.Sprint(key)] = normalize(elem)
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, elem := range val {
+			out[i] = normalize(elem)
+		}
+		return out
+	case []map[string]any:
+		out := make([]any, len(val))
+		for i, elem := range val {
+			out[i] = normalize(elem)
+		}
+		return out
+	case json.Number:
+		if n, err := val.Int64(); err == nil {
+			return n
+		}
+		// large unsigned values do not survive a float64
+		if n, err := strconv.ParseUint(val.String(), 10, 64); err == nil {
+			return n
+		}
+		n, _ := val.Float64()
+		return n
+	default:
+		return v
+	}
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	durationType        = reflect.TypeOf(time.Duration(0))
+)
+
+// coerce converts the strings read from an untyped format into the JSON types
+// that t expects. Values that do not parse are left as they are, so decoding
+// reports them.
+func coerce(v any, t reflect.Type) any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == durationType {
+		if str, ok := v.(string); ok {
+			if d, err := time.ParseDuration(str); err == nil {
+				return int64(d)
+			}
+			if n, err := strconv.ParseInt(str, 10, 64); err == nil {
+				return n
+			}
+		}
+		return v
+	}
+
+	if reflect.PointerTo(t).Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return v
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		values, ok := v.(map[string]any)
+		if !ok {
+			return v
+		}
+		fields := jsonFields(t)
+		out := map[string]any{}
+		for key, elem := range values {
+			if field, ok := fields[strings.ToLower(key)]; ok {
+				out[key] = coerce(elem, field.t)
+			} else {
+				out[key] = elem
+			}
+		}
+		return out
+	case reflect.Map:
+		values, ok := v.(map[string]any)
+		if !ok {
+			return v
+		}
+		out := map[string]any{}
+		for key, elem := range values {
+			out[key] = coerce(elem, t.Elem())
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return v
+		}
+		if str, ok := v.(string); ok {
+			v = splitList(str)
+		}
+		values, ok := v.([]any)
+		if !ok {
+			return v
+		}
+		out := make([]any, len(values))
+		for i, elem := range values {
+			out[i] = coerce(elem, t.Elem())
+		}
+		return out
+	}
+
+	str, ok := v.(string)
+	if !ok {
+		return v
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(str); err == nil {
+			return b
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, err := strconv.ParseInt(str, 10, 64); err == nil {
+			return n
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, err := strconv.ParseUint(str, 10, 64); err == nil {
+			return n
+		}
+	case reflect.Float32, reflect.Float64:
+		if n, err := strconv.ParseFloat(str, 64); err == nil {
+			return n
+		}
+	}
+
+	return v
+}
+
+type jsonField struct {
+	name   string
+	goName string
+	t      reflect.Type
+}
+
+// jsonFields maps the lower cased JSON name of each field of t to the field,
+// flattening embedded structs the way encoding/json does.
+func jsonFields(t reflect.Type) map[string]jsonField {
+	fields := map[string]jsonField{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" && options == "" {
+			continue
+		}
+
+		embedded := field.Type
+		if embedded.Kind() == reflect.Pointer {
+			embedded = embedded.Elem()
+		}
+		if field.Anonymous && name == "" && embedded.Kind() == reflect.Struct {
+			for key, value := range jsonFields(embedded) {
+				if _, ok := fields[key]; !ok {
+					fields[key] = value
+				}
+			}
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[strings.ToLower(name)] = jsonField{name: name, goName: field.Name, t: field.Type}
+	}
+
+	return fields
+}
+
+func splitList(str string) []any {
+	out := []any{}
+	if strings.TrimSpace(str) == "" {
+		return out
+	}
+	for _, part := range strings.Split(str, ",") {
+		out = append(out, strings.TrimSpace(part))
+	}
+	return out
+}
+
+func parseYAML(data []byte) (any, error) {
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func formatYAML(doc map[string]any) ([]byte, error) {
+	buf := bytes.NewBuffer([]byte{})
+	encoder := yaml.NewEncoder(buf)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func parseTOML(data []byte) (any, error) {
+	doc := map[string]any{}
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func formatTOML(doc map[string]any) ([]byte, error) {
+	if err := checkTOMLInts(doc, ""); err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	if err := toml.NewEncoder(buf).Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// checkTOMLInts rejects integers that TOML can write but not read back.
+func checkTOMLInts(v any, path string) error {
+	switch val := v.(type) {
+	case map[string]any:
+		for key, elem := range val {
+			if err := checkTOMLInts(elem, joinPath(path, key)); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for i, elem := range val {
+			if err := checkTOMLInts(elem, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case uint64:
+		if val > math.MaxInt64 {
+			return fmt.Errorf("%s: %d is too large for a TOML integer", path, val)
+		}
+	}
+	return nil
+}
+
+func parseINI(data []byte) (any, error) {
+	file, err := ini.Load(data)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := map[string]any{}
+
+	for _, section := range file.Sections() {
+		target := doc
+		if section.Name() != ini.DefaultSection {
+			for _, part := range strings.Split(section.Name(), ".") {
+				child, ok := target[part].(map[string]any)
+				if !ok {
+					child = map[string]any{}
+					target[part] = child
+				}
+				target = child
+			}
+		}
+
+		for _, key := range section.Keys() {
+			target[key.Name()] = key.Value()
+		}
+	}
+
+	return doc, nil
+}
+
+func formatINI(doc map[string]any) ([]byte, error) {
+	file := ini.Empty()
+
+	if err := addSection(file, "", doc); err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	if _, err := file.WriteTo(buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func addSection(file *ini.File, name string, values map[string]any) error {
+	section := file.Section(name)
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	children := []string{}
+
+	for _, key := range keys {
+		switch val := values[key].(type) {
+		case map[string]any:
+			children = append(children, key)
+		case []any:
+			items := []string{}
+			for _, item := range val {
+				switch item.(type) {
+				case map[string]any, []any:
+					return fmt.Errorf("%s: lists of objects or lists cannot be stored in INI", joinPath(name, key))
+				}
+				item := fmt.Sprint(item)
+				if strings.Contains(item, ",") {
+					return fmt.Errorf("%s: list values containing commas cannot be stored in INI", joinPath(name, key))
+				}
+				items = append(items, item)
+			}
+			section.Key(key).SetValue(strings.Join(items, ","))
+		default:
+			section.Key(key).SetValue(fmt.Sprint(val))
+		}
+	}
+
+	// sections are written after the keys of their parent
+	for _, key := range children {
+		if err := addSection(file, joinPath(name, key), values[key].(map[string]any)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
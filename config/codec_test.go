@@ -0,0 +1,114 @@
+package config
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type codecServer struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+}
+
+type codecConfig struct {
+	Name    string            `json:"name"`
+	Enabled bool              `json:"enabled"`
+	Ratio   float64           `json:"ratio"`
+	Count   int64             `json:"count"`
+	Big     uint64            `json:"big"`
+	Timeout time.Duration     `json:"timeout"`
+	Tags    []string          `json:"tags"`
+	Limits  map[string]int    `json:"limits"`
+	Server  codecServer       `json:"server"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		codec Codec
+		value codecConfig
+	}{
+		{"json", JSON, codecConfig{Name: "a", Big: math.MaxUint64, Tags: []string{"x", "y"}}},
+		{"yaml", YAML, codecConfig{Name: "a", Enabled: true, Ratio: 0.5, Count: -3, Big: 1 << 63, Timeout: time.Second, Tags: []string{"x,y"}, Limits: map[string]int{"cpu": 2}, Server: codecServer{"h", 80}}},
+		{"toml", TOML, codecConfig{Name: "a", Enabled: true, Ratio: 0.5, Count: math.MinInt64, Big: math.MaxInt64, Timeout: time.Second, Tags: []string{"x,y"}, Limits: map[string]int{"cpu": 2}, Server: codecServer{"h", 80}}},
+		{"ini", INI, codecConfig{Name: "a b", Enabled: true, Ratio: 0.5, Count: -3, Big: math.MaxUint64, Timeout: time.Minute, Tags: []string{"x", "y"}, Limits: map[string]int{"cpu": 2}, Server: codecServer{"h", 80}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := test.codec.Encode(test.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got codecConfig
+			if err := test.codec.Decode(data, &got); err != nil {
+				t.Fatalf("%s\n%s", err, data)
+			}
+
+			// empty collections are written as nothing by some formats
+			if len(got.Tags) == 0 && len(test.value.Tags) == 0 {
+				got.Tags = test.value.Tags
+			}
+			if len(got.Limits) == 0 && len(test.value.Limits) == 0 {
+				got.Limits = test.value.Limits
+			}
+			if !reflect.DeepEqual(got, test.value) {
+				t.Fatalf("got %+v, want %+v\n%s", got, test.value, data)
+			}
+		})
+	}
+}
+
+func TestCodecRejectsLossyValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		codec Codec
+		value codecConfig
+		err   string
+	}{
+		{"toml uint64", TOML, codecConfig{Big: 1 << 63}, "too large for a TOML integer"},
+		{"ini comma", INI, codecConfig{Tags: []string{"a,b"}}, "containing commas"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.codec.Encode(test.value)
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("got %v, want an error containing %q", err, test.err)
+			}
+		})
+	}
+}
+
+func TestCodecFor(t *testing.T) {
+	tests := map[string]Codec{
+		"c.json":    JSON,
+		"c.YAML":    YAML,
+		"c.yml":     YAML,
+		"c.toml":    TOML,
+		"c.ini":     INI,
+		"c":         JSON,
+		"c.unknown": JSON,
+	}
+
+	for path, want := range tests {
+		if got := CodecFor(path); !sameCodec(got, want) {
+			t.Errorf("%s: got %T, want %T", path, got, want)
+		}
+	}
+}
+
+// sameCodec compares codecs, which hold funcs and so cannot use ==.
+func sameCodec(a Codec, b Codec) bool {
+	docA, okA := a.(docCodec)
+	docB, okB := b.(docCodec)
+	if !okA || !okB {
+		return okA == okB && reflect.TypeOf(a) == reflect.TypeOf(b)
+	}
+	return reflect.ValueOf(docA.parse).Pointer() == reflect.ValueOf(docB.parse).Pointer()
+}
@@ -2,10 +2,9 @@ package config
 
 import (
 	"bytes"
-	"encoding/json"
-	"io"
 	"os"
 	"path/filepath"
+	"reflect"
 
 	"github.com/kirsle/configdir"
 	"github.com/lspaccatrosi16/go-cli-tools/internal/pkgError"
@@ -33,111 +32,228 @@ func GetCredentialsPath(appName string) (string, error) {
 	return filepath.Join(cpath, "credentials.json"), nil
 }
 
-func decodeConfigFile[T any](reader io.Reader) (T, error) {
-	var configContents T
+func decodeConfigFile[T any](file []byte, codec Codec) (T, error) {
+	configContents, err := decodeUnchecked[T](file, codec)
+	if err != nil {
+		return configContents, err
+	}
 
-	decoder := json.NewDecoder(reader)
+	if err := Validate(&configContents); err != nil {
+		return *new(T), err
+	}
 
-	for {
-		err := decoder.Decode(&configContents)
+	return configContents, nil
+}
 
-		if err == io.EOF {
-			break
-		} else if err != nil {
-			return *new(T), wrap(err)
-		}
+func decodeUnchecked[T any](file []byte, codec Codec) (T, error) {
+	var configContents T
+
+	// an empty file is the zero value, which is left to the caller to fill
+	if len(bytes.TrimSpace(file)) == 0 {
+		return configContents, nil
+	}
+
+	if err := codec.Decode(file, &configContents); err != nil {
+		return *new(T), wrap(err)
 	}
 
 	return configContents, nil
 }
 
-func encodeConfigFile[T any](config T) (*bytes.Buffer, error) {
-	buf := bytes.NewBuffer([]byte{})
-	encoder := json.NewEncoder(buf)
+func encodeConfigFile[T any](config T, codec Codec) ([]byte, error) {
+	if err := Validate(&config); err != nil {
+		return nil, err
+	}
 
-	encoder.SetIndent("", "\t")
+	return encodeUnchecked(config, codec)
+}
 
-	err := encoder.Encode(&config)
+func encodeUnchecked[T any](config T, codec Codec) ([]byte, error) {
+	file, err := codec.Encode(&config)
 
 	if err != nil {
 		return nil, wrap(err)
 	}
 
-	return buf, nil
+	return file, nil
+}
+
+func checkSchema[T any](file []byte, codec Codec, schema *Schema) error {
+	if schema == nil || len(bytes.TrimSpace(file)) == 0 {
+		return nil
+	}
+
+	converted, err := toJSON(codec, file, reflect.TypeOf(new(T)).Elem())
+	if err != nil {
+		return wrap(err)
+	}
+
+	return schema.Validate(converted)
+}
+
+// ReadConfig holds optional settings for reading a config file.
+type ReadConfig struct {
+	// Schema, when set, is checked against the file before it is decoded.
+	Schema *Schema
+	// Codec overrides the format picked from the file extension.
+	Codec Codec
+	// SkipValidate returns configs that fail Validate instead of an error,
+	// e.g. to let the user fix them in an editor.
+	SkipValidate bool
+}
+
+func decodeWithConfig[T any](file []byte, codec Codec, config ReadConfig) (T, error) {
+	if config.SkipValidate {
+		return decodeUnchecked[T](file, codec)
+	}
+	return decodeConfigFile[T](file, codec)
+}
+
+// WriteConfig holds optional settings for writing a config file.
+type WriteConfig struct {
+	// Codec overrides the format picked from the file extension.
+	Codec Codec
+	// Perm is used for new files, 0644 when zero. Existing files keep their
+	// permissions unless ForcePerm is set.
+	Perm      os.FileMode
+	ForcePerm bool
+}
+
+func codecFor(path string, codec Codec) Codec {
+	if codec != nil {
+		return codec
+	}
+	return CodecFor(path)
 }
 
 func ReadConfigFile[T any](path string, defaultJson []byte) (T, error) {
-	var reader io.Reader
+	return ReadConfigFileWithConfig[T](path, defaultJson, ReadConfig{})
+}
+
+// ReadConfigFileWithConfig is ReadConfigFile with extra settings. The default
+// is always JSON and is not checked against the schema.
+func ReadConfigFileWithConfig[T any](path string, defaultJson []byte, config ReadConfig) (T, error) {
+	return readConfigFile[T](path, defaultJson, config, false)
+}
 
-	fh, err := os.Open(path)
+// readConfigFile reads path, upgrading it first when it is out of date.
+// locked says whether the caller already holds the lock for path.
+func readConfigFile[T any](path string, defaultJson []byte, config ReadConfig, locked bool) (T, error) {
+	codec := codecFor(path, config.Codec)
+	file, err := os.ReadFile(path)
 
 	if err != nil {
-		if os.IsNotExist(err) {
-			reader = bytes.NewReader(defaultJson)
-		} else {
+		if !os.IsNotExist(err) {
 			return *new(T), wrap(err)
 		}
-	} else {
-		reader = fh
+		return decodeWithConfig[T](defaultJson, JSON, config)
 	}
 
-	defer fh.Close()
-
-	decodeRes, err := decodeConfigFile[T](reader)
+	if old, err := outdated[T](file, codec); err != nil {
+		return *new(T), err
+	} else if old {
+		file, err = migrateConfigFile[T](path, codec, locked)
+		if err != nil {
+			return *new(T), err
+		}
+	}
 
-	if err != nil {
-		return *new(T), wrap(err)
+	if err := checkSchema[T](file, codec, config.Schema); err != nil {
+		return *new(T), err
 	}
 
-	return decodeRes, nil
+	return decodeWithConfig[T](file, codec, config)
 }
 
 func ReadCloudConfigFile[T any](bucket storage.StorageProvider, key string) (T, error) {
+	return ReadCloudConfigFileWithConfig[T](bucket, key, ReadConfig{})
+}
+
+func ReadCloudConfigFileWithConfig[T any](bucket storage.StorageProvider, key string, config ReadConfig) (T, error) {
+	codec := codecFor(key, config.Codec)
 	file, err := bucket.GetFile(key)
 
 	if err != nil {
 		return *new(T), wrap(err)
 	}
 
-	reader := bytes.NewReader(file)
-
-	decodeRes, err := decodeConfigFile[T](reader)
+	file, err = migrateCloudConfigFile[T](bucket, key, file, codec)
 
 	if err != nil {
-		return *new(T), wrap(err)
+		return *new(T), err
 	}
 
-	return decodeRes, nil
+	if err := checkSchema[T](file, codec, config.Schema); err != nil {
+		return *new(T), err
+	}
+
+	return decodeWithConfig[T](file, codec, config)
 }
 
 func WriteConfigFile[T any](path string, config T) error {
-	reader, err := encodeConfigFile(config)
+	return WriteConfigFileWithConfig(path, config, WriteConfig{})
+}
+
+// WriteConfigFileWithConfig is WriteConfigFile with extra settings. The file
+// is replaced atomically while holding the lock from LockFile, and the
+// previous version is kept in path+".bak".
+func WriteConfigFileWithConfig[T any](path string, config T, options WriteConfig) error {
+	unlock, err := LockFile(path)
 
 	if err != nil {
-		return wrap(err)
+		return err
 	}
 
-	fh, err := os.Create(path)
+	defer unlock()
+
+	return writeLocked(path, config, options)
+}
+
+func writeLocked[T any](path string, config T, options WriteConfig) error {
+	file, err := encodeConfigFile(config, codecFor(path, options.Codec))
 
 	if err != nil {
-		return wrap(err)
+		return err
 	}
 
-	defer fh.Close()
+	return WriteFileAtomicWithConfig(path, file, AtomicConfig{Perm: options.Perm, ForcePerm: options.ForcePerm})
+}
 
-	io.Copy(fh, reader)
+// UpdateConfigFile reads the config at path, lets update change it and writes
+// it back, holding the lock from LockFile throughout so that concurrent
+// updates are not lost.
+func UpdateConfigFile[T any](path string, defaultJson []byte, update func(*T) error) error {
+	unlock, err := LockFile(path)
 
-	return nil
-}
+	if err != nil {
+		return err
+	}
 
-func WritCloudConfigFile[T any](bucket storage.StorageProvider, key string, config T) error {
-	reader, err := encodeConfigFile(config)
+	defer unlock()
+
+	config, err := readConfigFile[T](path, defaultJson, ReadConfig{}, true)
 
 	if err != nil {
+		return err
+	}
+
+	if err := update(&config); err != nil {
 		return wrap(err)
 	}
 
-	file := reader.Bytes()
+	return writeLocked(path, config, WriteConfig{})
+}
+
+func WritCloudConfigFile[T any](bucket storage.StorageProvider, key string, config T) error {
+	return WriteCloudConfigFileWithConfig(bucket, key, config, WriteConfig{})
+}
+
+func WriteCloudConfigFileWithConfig[T any](bucket storage.StorageProvider, key string, config T, options WriteConfig) error {
+	file, err := encodeConfigFile(config, codecFor(key, options.Codec))
+
+	if err != nil {
+		return err
+	}
 
 	err = bucket.UploadFile(key, file)
 
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type bounds struct {
+	Min int
+	Max int
+}
+
+func (b bounds) Validate() error {
+	if b.Min > b.Max {
+		return fmt.Errorf("min > max")
+	}
+	return nil
+}
+
+func TestReadSkipValidate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "c.json")
+	if err := os.WriteFile(path, []byte(`{"Min":5,"Max":1}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ReadConfigFile[bounds](path, nil); err == nil {
+		t.Fatal("expected an invalid file to be rejected")
+	}
+
+	cfg, err := ReadConfigFileWithConfig[bounds](path, nil, ReadConfig{SkipValidate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg != (bounds{5, 1}) {
+		t.Fatalf("got %+v", cfg)
+	}
+}
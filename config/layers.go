@@ -0,0 +1,610 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/lspaccatrosi16/go-cli-tools/args"
+)
+
+// extensions are tried in order when looking for a config file without one.
+var extensions = []string{".json", ".yaml", ".yml", ".toml", ".ini"}
+
+// LayerConfig describes where the layers of a config come from. Layers are
+// applied in order, each overriding the ones before it:
+//
+//  1. the defaults
+//  2. the system file, /etc/<app>/config
+//  3. the user file, config in GetConfigPath(app)
+//  4. the project file, .<app> in the working directory or the closest parent
+//  5. environment variables, e.g. <APP>_SERVER_PORT for Server.Port
+//  6. flags given on the command line, mapped with Flags
+//
+// Files may have any extension with a codec and are skipped when missing.
+type LayerConfig struct {
+	AppName string
+	// SystemPath, UserPath and ProjectFile override the default locations.
+	// ProjectFile is a file name looked for in every parent directory.
+	SystemPath  string
+	UserPath    string
+	ProjectFile string
+	// EnvPrefix defaults to the upper cased app name followed by _. Set it to
+	// "-" to ignore the environment.
+	EnvPrefix string
+	// Flags maps flag names registered with the args package to paths such
+	// as "Server.Port".
+	Flags map[string]string
+	// Schema, when set, is checked against the merged config.
+	Schema *Schema
+}
+
+// Source says which layer set a value, and from where.
+type Source struct {
+	Layer    string
+	Location string
+}
+
+func (s Source) String() string {
+	if s.Location == "" {
+		return s.Layer
+	}
+	return fmt.Sprintf("%s (%s)", s.Layer, s.Location)
+}
+
+// Layered is a config merged from several layers, remembering which layer
+// set each value. Value may be changed; SaveUser writes the changes to the
+// user file.
+type Layered[T any] struct {
+	Value   T
+	sources map[string]Source
+	// userPath is where SaveUser writes, even when no user file exists yet
+	userPath string
+	// loaded is Value as it was read or last saved
+	loaded map[string]any
+}
+
+// Where returns the layer that set the value at path, e.g. "Server.Port".
+// Paths are matched without regard to case. Values changed in Value since
+// the config was read or saved are reported as the "unsaved" layer.
+func (l *Layered[T]) Where(path string) Source {
+	if changes, err := l.changes(); err == nil {
+		for _, change := range changes {
+			if within(path, change.path) {
+				return Source{Layer: "unsaved"}
+			}
+		}
+	}
+
+	best := ""
+	for key := range l.sources {
+		if strings.EqualFold(key, path) {
+			return l.sources[key]
+		}
+		// a layer that replaced a whole list or map set everything inside it
+		if within(path, key) && len(key) > len(best) {
+			best = key
+		}
+	}
+
+	if best != "" {
+		return l.sources[best]
+	}
+	return Source{Layer: "default"}
+}
+
+// within reports whether path is parent or inside it, ignoring case.
+func within(path string, parent string) bool {
+	lower, lowerParent := strings.ToLower(path), strings.ToLower(parent)
+	return lower == lowerParent || strings.HasPrefix(lower, lowerParent+".") || strings.HasPrefix(lower, lowerParent+"[")
+}
+
+// SaveUser writes the values changed in Value since the config was read to
+// the user file, leaving everything else in it as it was. Values from other
+// layers are not copied into it, so pass SaveUser rather than a function
+// writing all of Value when saving changes made with structconfig. Keys
+// removed from maps are only removed from the user file, so a lower layer
+// may still set them.
+func (l *Layered[T]) SaveUser() error {
+	if err := Validate(&l.Value); err != nil {
+		return err
+	}
+
+	changes, err := l.changes()
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	unlock, err := LockFile(l.userPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	codec := CodecFor(l.userPath)
+	user := map[string]any{}
+
+	file, err := os.ReadFile(l.userPath)
+	if err != nil && !os.IsNotExist(err) {
+		return wrap(err)
+	}
+	if len(bytes.TrimSpace(file)) > 0 {
+		converted, err := toJSON(codec, file, reflect.TypeOf(&l.Value).Elem())
+		if err != nil {
+			return wrap(fmt.Errorf("%s: %s", l.userPath, err.Error()))
+		}
+		user, err = parseDoc(converted)
+		if err != nil {
+			return wrap(fmt.Errorf("%s: %s", l.userPath, err.Error()))
+		}
+	}
+
+	for _, change := range changes {
+		change.apply(user)
+	}
+
+	data, err := codec.Encode(user)
+	if err != nil {
+		return wrap(err)
+	}
+	if err := WriteFileAtomic(l.userPath, data, 0o644); err != nil {
+		return err
+	}
+
+	current, err := valueDoc(l.Value)
+	if err != nil {
+		return err
+	}
+	l.loaded = current
+	for _, change := range changes {
+		setSource(l.sources, change.path, Source{Layer: "user", Location: l.userPath})
+	}
+
+	return nil
+}
+
+func (l *Layered[T]) changes() ([]docChange, error) {
+	current, err := valueDoc(l.Value)
+	if err != nil {
+		return nil, err
+	}
+	return diffDoc(l.loaded, current, reflect.TypeOf(&l.Value).Elem(), nil, ""), nil
+}
+
+func valueDoc(v any) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, wrap(err)
+	}
+	doc, err := parseDoc(data)
+	if err != nil {
+		return nil, wrap(err)
+	}
+	return doc, nil
+}
+
+// docChange is a value that differs between two documents, at keys in the
+// document and path in Go field names.
+type docChange struct {
+	keys    []string
+	path    string
+	value   any
+	removed bool
+}
+
+func (c docChange) apply(doc map[string]any) {
+	target := doc
+	for _, key := range c.keys[:len(c.keys)-1] {
+		child, ok := target[key].(map[string]any)
+		if !ok {
+			child = map[string]any{}
+			target[key] = child
+		}
+		target = child
+	}
+
+	last := c.keys[len(c.keys)-1]
+	// drop other spellings of the same field, as encoding/json ignores case
+	for existing := range target {
+		if existing != last && strings.EqualFold(existing, last) {
+			delete(target, existing)
+		}
+	}
+
+	if c.removed {
+		delete(target, last)
+	} else {
+		target[last] = c.value
+	}
+}
+
+// diffDoc lists what differs between before and after, going into objects
+// key by key the way mergeDoc merges them.
+func diffDoc(before map[string]any, after map[string]any, t reflect.Type, keys []string, path string) []docChange {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	names := []string{}
+	for key := range before {
+		names = append(names, key)
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			names = append(names, key)
+		}
+	}
+	sort.Strings(names)
+
+	changes := []docChange{}
+	for _, key := range names {
+		_, childPath, childType := childOf(t, path, key)
+		childKeys := append(append([]string{}, keys...), key)
+
+		old, hadOld := before[key]
+		value, hasValue := after[key]
+
+		oldMap, oldIsMap := old.(map[string]any)
+		valueMap, valueIsMap := value.(map[string]any)
+		if oldIsMap && valueIsMap {
+			changes = append(changes, diffDoc(oldMap, valueMap, childType, childKeys, childPath)...)
+			continue
+		}
+
+		if hadOld == hasValue && reflect.DeepEqual(old, value) {
+			continue
+		}
+		changes = append(changes, docChange{keys: childKeys, path: childPath, value: value, removed: !hasValue})
+	}
+
+	return changes
+}
+
+// ReadLayeredConfig merges every layer described by config on top of defaults.
+// Objects are merged key by key; lists and values replace what was there.
+func ReadLayeredConfig[T any](config LayerConfig, defaults T) (*Layered[T], error) {
+	t := reflect.TypeOf(&defaults).Elem()
+	layered := &Layered[T]{sources: map[string]Source{}}
+
+	data, err := json.Marshal(defaults)
+	if err != nil {
+		return nil, wrap(err)
+	}
+	doc, err := parseDoc(data)
+	if err != nil {
+		return nil, wrap(err)
+	}
+
+	files := []Source{}
+
+	system := config.SystemPath
+	if system == "" {
+		system = findFile(filepath.Join("/etc", config.AppName, "config"))
+	}
+	files = append(files, Source{Layer: "system", Location: system})
+
+	user := config.UserPath
+	if user == "" {
+		dir, err := GetConfigPath(config.AppName)
+		if err != nil {
+			return nil, err
+		}
+		user = findFile(filepath.Join(dir, "config"))
+		layered.userPath = filepath.Join(dir, "config.json")
+	}
+	if user != "" {
+		layered.userPath = user
+	}
+	files = append(files, Source{Layer: "user", Location: user})
+
+	project, err := findProjectFile(config)
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, Source{Layer: "project", Location: project})
+
+	for _, source := range files {
+		if source.Location == "" {
+			continue
+		}
+
+		file, err := os.ReadFile(source.Location)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, wrap(err)
+		}
+		if len(bytes.TrimSpace(file)) == 0 {
+			continue
+		}
+
+		converted, err := toJSON(CodecFor(source.Location), file, t)
+		if err != nil {
+			return nil, wrap(fmt.Errorf("%s: %s", source.Location, err.Error()))
+		}
+		layer, err := parseDoc(converted)
+		if err != nil {
+			return nil, wrap(fmt.Errorf("%s: %s", source.Location, err.Error()))
+		}
+
+		mergeDoc(doc, layer, t, "", source, layered.sources)
+	}
+
+	fields := leafFields(t, nil, "", map[reflect.Type]bool{})
+
+	if config.EnvPrefix != "-" {
+		prefix := config.EnvPrefix
+		if prefix == "" {
+			prefix = strings.ToUpper(config.AppName) + "_"
+		}
+
+		for _, field := range fields {
+			name := prefix + strings.ToUpper(strings.NewReplacer(".", "_").Replace(field.path))
+			if value, ok := os.LookupEnv(name); ok {
+				setLeaf(doc, field, value, Source{Layer: "env", Location: name}, layered.sources)
+			}
+		}
+	}
+
+	if len(config.Flags) > 0 {
+		set, err := args.GetSetFlags()
+		if err != nil {
+			return nil, wrap(err)
+		}
+
+		for _, name := range sortedNames(config.Flags) {
+			value, ok := set[name]
+			if !ok {
+				continue
+			}
+			field, ok := findLeaf(fields, config.Flags[name])
+			if !ok {
+				return nil, wrap(fmt.Errorf("flag %s is mapped to unknown path %s", name, config.Flags[name]))
+			}
+			setLeaf(doc, field, value, Source{Layer: "flag", Location: "-" + name}, layered.sources)
+		}
+	}
+
+	merged, err := json.Marshal(doc)
+	if err != nil {
+		return nil, wrap(err)
+	}
+
+	if config.Schema != nil {
+		if err := config.Schema.Validate(merged); err != nil {
+			return nil, err
+		}
+	}
+
+	value, err := decodeConfigFile[T](merged, JSON)
+	if err != nil {
+		return nil, err
+	}
+
+	layered.Value = value
+	layered.loaded, err = valueDoc(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return layered, nil
+}
+
+func parseDoc(data []byte) (map[string]any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	values, ok := doc.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("config must be an object")
+	}
+	return values, nil
+}
+
+// findFile returns base itself or with the first extension that exists, or
+// "" when there is none.
+func findFile(base string) string {
+	for _, candidate := range append([]string{base}, suffixed(base)...) {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate
+		}
+	}
+	return ""
+}
+
+func suffixed(base string) []string {
+	out := []string{}
+	for _, ext := range extensions {
+		out = append(out, base+ext)
+	}
+	return out
+}
+
+func findProjectFile(config LayerConfig) (string, error) {
+	names := []string{config.ProjectFile}
+	if config.ProjectFile == "" {
+		names = suffixed("." + config.AppName)
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", wrap(err)
+	}
+
+	for {
+		for _, name := range names {
+			candidate := filepath.Join(dir, name)
+			if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+				return candidate, nil
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// mergeDoc merges src into dst, using t to name paths by their Go fields and
+// recording source for every value src sets.
+func mergeDoc(dst map[string]any, src map[string]any, t reflect.Type, path string, source Source, sources map[string]Source) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	keys := make([]string, 0, len(src))
+	for key := range src {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		name, childPath, childType := childOf(t, path, key)
+
+		if t != nil && t.Kind() == reflect.Struct {
+			if childType == nil {
+				// unknown fields are kept so decoding behaves as for one file
+				dst[key] = src[key]
+				continue
+			}
+			// encoding/json matches names without case, so drop other spellings
+			for existing := range dst {
+				if existing != name && strings.EqualFold(existing, name) {
+					delete(dst, existing)
+				}
+			}
+		}
+
+		srcMap, srcIsMap := src[key].(map[string]any)
+		dstMap, dstIsMap := dst[name].(map[string]any)
+		if srcIsMap && (dstIsMap || dst[name] == nil) {
+			if !dstIsMap {
+				dstMap = map[string]any{}
+				dst[name] = dstMap
+			}
+			mergeDoc(dstMap, srcMap, childType, childPath, source, sources)
+			continue
+		}
+
+		dst[name] = src[key]
+		setSource(sources, childPath, source)
+	}
+}
+
+// childOf names the value under key in a document for t: its key as
+// encoding/json writes it, its path in Go field names and its type, which is
+// nil when unknown.
+func childOf(t reflect.Type, path string, key string) (string, string, reflect.Type) {
+	switch {
+	case t != nil && t.Kind() == reflect.Struct:
+		field, ok := jsonFields(t)[strings.ToLower(key)]
+		if !ok {
+			return key, joinPath(path, key), nil
+		}
+		return field.name, joinPath(path, field.goName), field.t
+	case t != nil && t.Kind() == reflect.Map:
+		return key, fmt.Sprintf("%s[%s]", path, key), t.Elem()
+	default:
+		return key, joinPath(path, key), nil
+	}
+}
+
+func setSource(sources map[string]Source, path string, source Source) {
+	for key := range sources {
+		if strings.HasPrefix(key, path+".") || strings.HasPrefix(key, path+"[") {
+			delete(sources, key)
+		}
+	}
+	sources[path] = source
+}
+
+type leafField struct {
+	path string
+	keys []string
+	t    reflect.Type
+}
+
+// leafFields lists every value in t that can be set from a single string:
+// scalars, text types and lists of them.
+func leafFields(t reflect.Type, keys []string, path string, seen map[reflect.Type]bool) []leafField {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct ||
+		reflect.PointerTo(t).Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		if t.Kind() == reflect.Map || len(keys) == 0 {
+			return nil
+		}
+		return []leafField{{path: path, keys: keys, t: t}}
+	}
+
+	if seen[t] {
+		return nil
+	}
+	seen[t] = true
+	defer delete(seen, t)
+
+	fields := jsonFields(t)
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := []leafField{}
+	for _, name := range names {
+		field := fields[name]
+		childKeys := append(append([]string{}, keys...), field.name)
+		out = append(out, leafFields(field.t, childKeys, joinPath(path, field.goName), seen)...)
+	}
+	return out
+}
+
+func findLeaf(fields []leafField, path string) (leafField, bool) {
+	for _, field := range fields {
+		if strings.EqualFold(field.path, path) {
+			return field, true
+		}
+	}
+	return leafField{}, false
+}
+
+func setLeaf(doc map[string]any, field leafField, value string, source Source, sources map[string]Source) {
+	target := doc
+	for _, key := range field.keys[:len(field.keys)-1] {
+		child, ok := target[key].(map[string]any)
+		if !ok {
+			child = map[string]any{}
+			target[key] = child
+		}
+		target = child
+	}
+
+	target[field.keys[len(field.keys)-1]] = coerce(value, field.t)
+	setSource(sources, field.path, source)
+}
+
+func sortedNames(values map[string]string) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type layeredServer struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+}
+
+type layeredConfig struct {
+	Name   string         `json:"name"`
+	Server layeredServer  `json:"server"`
+	Tags   []string       `json:"tags"`
+	Limits map[string]int `json:"limits"`
+}
+
+func writeLayer(t *testing.T, path string, contents string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readLayers(t *testing.T, dir string) *Layered[layeredConfig] {
+	t.Helper()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	t.Setenv("LAYERTEST_SERVER_HOST", "env-host")
+
+	layered, err := ReadLayeredConfig(LayerConfig{
+		AppName:    "layertest",
+		SystemPath: filepath.Join(dir, "system.yaml"),
+		UserPath:   filepath.Join(dir, "user.json"),
+	}, layeredConfig{Name: "default", Server: layeredServer{Host: "localhost", Port: 80}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return layered
+}
+
+func TestReadLayeredConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeLayer(t, filepath.Join(dir, "system.yaml"), "server:\n  port: 8080\nlimits:\n  cpu: 1\n")
+	writeLayer(t, filepath.Join(dir, "user.json"), `{"limits":{"mem":2},"tags":["u"]}`)
+	writeLayer(t, filepath.Join(dir, ".layertest.toml"), "name = \"project\"\n")
+
+	layered := readLayers(t, dir)
+
+	want := layeredConfig{
+		Name:   "project",
+		Server: layeredServer{Host: "env-host", Port: 8080},
+		Tags:   []string{"u"},
+		Limits: map[string]int{"cpu": 1, "mem": 2},
+	}
+	if !equalJSON(t, layered.Value, want) {
+		t.Fatalf("got %+v, want %+v", layered.Value, want)
+	}
+
+	tests := map[string]string{
+		"Name":         "project",
+		"Server.Port":  "system",
+		"server.host":  "env",
+		"Tags[0]":      "user",
+		"Limits[cpu]":  "system",
+		"Limits[mem]":  "user",
+		"Limits[none]": "default",
+	}
+	for path, layer := range tests {
+		if got := layered.Where(path).Layer; got != layer {
+			t.Errorf("%s: got %s, want %s", path, got, layer)
+		}
+	}
+}
+
+func TestSaveUserWritesOnlyChanges(t *testing.T) {
+	dir := t.TempDir()
+	writeLayer(t, filepath.Join(dir, "system.yaml"), "server:\n  port: 8080\n")
+	writeLayer(t, filepath.Join(dir, "user.json"), `{"tags":["u"]}`)
+
+	layered := readLayers(t, dir)
+	layered.Value.Name = "changed"
+	layered.Value.Limits = map[string]int{"cpu": 4}
+
+	if got := layered.Where("Name").Layer; got != "unsaved" {
+		t.Fatalf("got %s before saving, want unsaved", got)
+	}
+
+	if err := layered.SaveUser(); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := os.ReadFile(filepath.Join(dir, "user.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leaked := range []string{"8080", "env-host", "localhost"} {
+		if strings.Contains(string(saved), leaked) {
+			t.Fatalf("user file holds %s from another layer:\n%s", leaked, saved)
+		}
+	}
+
+	if got := layered.Where("Name").Layer; got != "user" {
+		t.Fatalf("got %s after saving, want user", got)
+	}
+
+	reread := readLayers(t, dir)
+	if !equalJSON(t, reread.Value, layered.Value) {
+		t.Fatalf("got %+v after reading again, want %+v", reread.Value, layered.Value)
+	}
+}
+
+func equalJSON(t *testing.T, a any, b any) bool {
+	t.Helper()
+	docA, err := valueDoc(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	docB, err := valueDoc(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(diffDoc(docA, docB, nil, nil, "")) == 0
+}
@@ -0,0 +1,35 @@
+//go:build !windows
+
+package config
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func lockFile(fh *os.File) error {
+	for {
+		err := unix.Flock(int(fh.Fd()), unix.LOCK_EX)
+		if err != unix.EINTR {
+			return err
+		}
+	}
+}
+
+func unlockFile(fh *os.File) error {
+	return unix.Flock(int(fh.Fd()), unix.LOCK_UN)
+}
+
+// syncDir makes a rename in dir durable.
+func syncDir(dir string) error {
+	fh, err := os.Open(dir)
+	if err != nil {
+		return wrap(err)
+	}
+	defer fh.Close()
+
+	// some file systems cannot sync directories, which is not worth failing for
+	fh.Sync()
+	return nil
+}
@@ -0,0 +1,269 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/lspaccatrosi16/go-cli-tools/storage"
+)
+
+// VersionKey is the top level key holding the version of a config file. A
+// config struct with migrations must have a field stored under it, e.g.
+//
+//	Version int `json:"version"`
+//
+// and set it to the latest version in its defaults. Files without it are
+// version 0.
+const VersionKey = "version"
+
+type migration struct {
+	to      int
+	migrate func(map[string]any) error
+}
+
+var migrations = map[reflect.Type]map[int]migration{}
+
+// RegisterMigration adds a step that upgrades files for T from one version to
+// a later one by changing the decoded document, e.g. renaming a key. Reading
+// a config applies every step from the file's version onwards before decoding
+// it, then writes the upgraded file back and keeps the original in
+// <path>.v<version>.bak. It panics when T has no field stored under
+// VersionKey, since the upgraded version could not be written back.
+func RegisterMigration[T any](from int, to int, migrate func(map[string]any) error) {
+	if to <= from {
+		panic(fmt.Errorf("migration must go to a later version, not from %d to %d", from, to))
+	}
+
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if !hasVersionField(t) {
+		panic(fmt.Errorf("%s has no field stored under %q to hold its version", t, VersionKey))
+	}
+	if migrations[t] == nil {
+		migrations[t] = map[int]migration{}
+	}
+	if _, ok := migrations[t][from]; ok {
+		panic(fmt.Errorf("migration from version %d is already registered for %s", from, t))
+	}
+
+	migrations[t][from] = migration{to: to, migrate: migrate}
+}
+
+func hasVersionField(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	_, ok := jsonFields(t)[VersionKey]
+	return ok
+}
+
+// migrateDoc runs the migrations for t from the version of doc onwards and
+// reports the version doc started at and whether anything ran.
+func migrateDoc(doc map[string]any, t reflect.Type) (int, bool, error) {
+	key, version, err := docVersion(doc)
+	if err != nil {
+		return 0, false, err
+	}
+
+	start := version
+	steps := migrations[t]
+
+	for {
+		step, ok := steps[version]
+		if !ok {
+			break
+		}
+		if err := step.migrate(doc); err != nil {
+			return start, false, fmt.Errorf("migrating from version %d to %d: %s", version, step.to, err.Error())
+		}
+		version = step.to
+		doc[key] = version
+	}
+
+	return start, version != start, nil
+}
+
+func docVersion(doc map[string]any) (string, int, error) {
+	for key, value := range doc {
+		if !strings.EqualFold(key, VersionKey) {
+			continue
+		}
+
+		switch v := value.(type) {
+		case int64:
+			return key, int(v), nil
+		case float64:
+			return key, int(v), nil
+		case string:
+			n, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil {
+				return key, 0, fmt.Errorf("invalid %s %q", key, v)
+			}
+			return key, n, nil
+		default:
+			return key, 0, fmt.Errorf("invalid %s %v", key, v)
+		}
+	}
+
+	return VersionKey, 0, nil
+}
+
+// fileDoc decodes the contents of a config file for T into a document, or
+// returns nil when T has no migrations.
+func fileDoc[T any](file []byte, codec Codec) (map[string]any, reflect.Type, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if len(migrations[t]) == 0 || len(bytes.TrimSpace(file)) == 0 {
+		return nil, t, nil
+	}
+
+	converted, err := toJSON(codec, file, t)
+	if err != nil {
+		return nil, t, wrap(err)
+	}
+	doc, err := parseDoc(converted)
+	if err != nil {
+		return nil, t, wrap(err)
+	}
+
+	return normalize(doc).(map[string]any), t, nil
+}
+
+// outdated reports whether a migration applies to the contents of a config
+// file for T, reading only its version.
+func outdated[T any](file []byte, codec Codec) (bool, error) {
+	doc, t, err := fileDoc[T](file, codec)
+	if err != nil || doc == nil {
+		return false, err
+	}
+
+	_, version, err := docVersion(doc)
+	if err != nil {
+		return false, wrap(err)
+	}
+
+	_, ok := migrations[t][version]
+	return ok, nil
+}
+
+// migrateFile upgrades the contents of a config file for T, returning them in
+// the same format. ok is false when there was nothing to do.
+func migrateFile[T any](file []byte, codec Codec) ([]byte, int, bool, error) {
+	doc, t, err := fileDoc[T](file, codec)
+	if err != nil || doc == nil {
+		return file, 0, false, err
+	}
+
+	from, ok, err := migrateDoc(doc, t)
+	if err != nil || !ok {
+		return file, from, false, wrap(err)
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, from, false, wrap(err)
+	}
+
+	// the upgraded file is validated when it is read, not here, so that
+	// files that need fixing can still be opened for editing
+	config, err := decodeUnchecked[T](data, JSON)
+	if err != nil {
+		return nil, from, false, err
+	}
+
+	upgraded, err := encodeUnchecked(config, codec)
+	if err != nil {
+		return nil, from, false, err
+	}
+
+	return upgraded, from, true, nil
+}
+
+// migrateConfigFile upgrades the file at path under its lock, keeping the
+// original, and returns the new contents.
+func migrateConfigFile[T any](path string, codec Codec, locked bool) ([]byte, error) {
+	if !locked {
+		unlock, err := LockFile(path)
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+	}
+
+	// read again, another process may have upgraded it already
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return nil, wrap(err)
+	}
+
+	upgraded, from, ok, err := migrateFile[T](original, codec)
+	if err != nil || !ok {
+		return upgraded, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, wrap(err)
+	}
+
+	if err := replaceFile(backupPath(path, from), original, info.Mode().Perm()); err != nil {
+		return nil, err
+	}
+	if err := replaceFile(path, upgraded, info.Mode().Perm()); err != nil {
+		return nil, err
+	}
+
+	return upgraded, nil
+}
+
+// backupPath names the copy kept of a file at version from, never one that
+// already exists: earlier copies may be the only ones left of older files.
+func backupPath(path string, from int) string {
+	return freeBackup(path, from, func(name string) bool {
+		_, err := os.Stat(name)
+		return !os.IsNotExist(err)
+	})
+}
+
+func freeBackup(path string, from int, exists func(string) bool) string {
+	backup := fmt.Sprintf("%s.v%d.bak", path, from)
+	for i := 1; exists(backup); i++ {
+		backup = fmt.Sprintf("%s.v%d.%d.bak", path, from, i)
+	}
+	return backup
+}
+
+func migrateCloudConfigFile[T any](bucket storage.StorageProvider, key string, original []byte, codec Codec) ([]byte, error) {
+	upgraded, from, ok, err := migrateFile[T](original, codec)
+	if err != nil || !ok {
+		return upgraded, err
+	}
+
+	keys, err := bucket.ListKeys()
+	if err != nil {
+		return nil, wrap(err)
+	}
+	backup := freeBackup(key, from, func(name string) bool {
+		for _, existing := range keys {
+			if existing == name {
+				return true
+			}
+		}
+		return false
+	})
+
+	if err := bucket.UploadFile(backup, original); err != nil {
+		return nil, wrap(err)
+	}
+	if err := bucket.UploadFile(key, upgraded); err != nil {
+		return nil, wrap(err)
+	}
+
+	return upgraded, nil
+}
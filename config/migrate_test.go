@@ -0,0 +1,119 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type migrated struct {
+	Version int    `json:"version"`
+	Count   int    `json:"count"`
+	Name    string `json:"name"`
+}
+
+type unversioned struct {
+	Count int
+}
+
+func TestMigrateFile(t *testing.T) {
+	calls := 0
+	delete(migrations, typeOf[migrated]())
+	RegisterMigration[migrated](0, 1, func(doc map[string]any) error {
+		calls++
+		doc["count"] = doc["count"].(int64) * 2
+		return nil
+	})
+	RegisterMigration[migrated](1, 3, func(doc map[string]any) error {
+		calls++
+		doc["name"] = doc["title"]
+		delete(doc, "title")
+		return nil
+	})
+
+	tests := []struct {
+		name  string
+		file  string
+		codec Codec
+		want  migrated
+		ok    bool
+		calls int
+	}{
+		{"no version", `{"count":3,"title":"a"}`, JSON, migrated{3, 6, "a"}, true, 2},
+		{"middle", `{"version":1,"count":3,"title":"a"}`, JSON, migrated{3, 3, "a"}, true, 1},
+		{"latest", `{"version":3,"count":3,"name":"a"}`, JSON, migrated{}, false, 0},
+		{"yaml", "count: 4\ntitle: b\n", YAML, migrated{3, 8, "b"}, true, 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calls = 0
+			upgraded, _, ok, err := migrateFile[migrated]([]byte(test.file), test.codec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != test.ok || calls != test.calls {
+				t.Fatalf("got ok=%v calls=%d, want ok=%v calls=%d", ok, calls, test.ok, test.calls)
+			}
+			if !ok {
+				return
+			}
+			got, err := decodeConfigFile[migrated](upgraded, test.codec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Fatalf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestReadMigratesOnce(t *testing.T) {
+	calls := 0
+	delete(migrations, typeOf[migrated]())
+	RegisterMigration[migrated](0, 1, func(doc map[string]any) error {
+		calls++
+		doc["count"] = doc["count"].(int64) * 2
+		return nil
+	})
+
+	path := filepath.Join(t.TempDir(), "c.json")
+	for i, original := range []string{`{"count":3}`, `{"count":5}`} {
+		if err := os.WriteFile(path, []byte(original), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		for j := 0; j < 3; j++ {
+			if _, err := ReadConfigFile[migrated](path, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if calls != i+1 {
+			t.Fatalf("migration ran %d times, want %d", calls, i+1)
+		}
+	}
+
+	for backup, want := range map[string]string{".v0.bak": `{"count":3}`, ".v0.1.bak": `{"count":5}`} {
+		got, err := os.ReadFile(path + backup)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Fatalf("%s holds %s, want %s", backup, got, want)
+		}
+	}
+}
+
+func TestRegisterMigrationNeedsVersion(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic for a type without a version field")
+		}
+	}()
+	RegisterMigration[unversioned](0, 1, func(map[string]any) error { return nil })
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
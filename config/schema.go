@@ -0,0 +1,228 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Schema is the subset of JSON Schema used to describe config files.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	PropertyNames        *Schema            `json:"propertyNames,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Default              any                `json:"default,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	MinProperties        *int               `json:"minProperties,omitempty"`
+	MaxProperties        *int               `json:"maxProperties,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	ReadOnly             bool               `json:"readOnly,omitempty"`
+	WriteOnly            bool               `json:"writeOnly,omitempty"`
+}
+
+// Validate checks a JSON document against the schema. Every problem is
+// reported with the path to the offending value, e.g. "Server.Port" or
+// "Hosts[2]".
+func (s *Schema) Validate(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return wrap(err)
+	}
+
+	errs := s.check(doc, "")
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return wrap(errors.Join(errs...))
+}
+
+func (s *Schema) check(v any, path string) []error {
+	fail := func(format string, a ...any) []error {
+		name := path
+		if name == "" {
+			name = "<root>"
+		}
+		return []error{fmt.Errorf("%s: %s", name, fmt.Sprintf(format, a...))}
+	}
+
+	if len(s.AnyOf) > 0 {
+		for _, option := range s.AnyOf {
+			if len(option.check(v, path)) == 0 {
+				return nil
+			}
+		}
+		// report against the first option, which is the non null one
+		return s.AnyOf[0].check(v, path)
+	}
+
+	if s.Type != "" && !hasType(v, s.Type) {
+		return fail("expected %s, found %s", s.Type, typeName(v))
+	}
+
+	if len(s.Enum) > 0 && !inEnum(v, s.Enum) {
+		options := []string{}
+		for _, option := range s.Enum {
+			options = append(options, fmt.Sprint(option))
+		}
+		return fail("must be one of %s", strings.Join(options, ", "))
+	}
+
+	errs := []error{}
+
+	switch val := v.(type) {
+	case json.Number:
+		n, _ := val.Float64()
+		if s.Minimum != nil && n < *s.Minimum {
+			return fail("must be at least %v", *s.Minimum)
+		}
+		if s.Maximum != nil && n > *s.Maximum {
+			return fail("must be at most %v", *s.Maximum)
+		}
+	case string:
+		size := utf8.RuneCountInString(val)
+		if s.MinLength != nil && size < *s.MinLength {
+			return fail("must have at least %d characters", *s.MinLength)
+		}
+		if s.MaxLength != nil && size > *s.MaxLength {
+			return fail("must have at most %d characters", *s.MaxLength)
+		}
+		if s.Pattern != "" {
+			re, err := regexp.Compile(s.Pattern)
+			if err != nil {
+				return fail("invalid pattern in schema: %s", err.Error())
+			}
+			if !re.MatchString(val) {
+				return fail("must match %s", s.Pattern)
+			}
+		}
+	case []any:
+		if s.MinItems != nil && len(val) < *s.MinItems {
+			return fail("must have at least %d elements", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(val) > *s.MaxItems {
+			return fail("must have at most %d elements", *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, elem := range val {
+				errs = append(errs, s.Items.check(elem, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	case map[string]any:
+		if s.MinProperties != nil && len(val) < *s.MinProperties {
+			return fail("must have at least %d entries", *s.MinProperties)
+		}
+		if s.MaxProperties != nil && len(val) > *s.MaxProperties {
+			return fail("must have at most %d entries", *s.MaxProperties)
+		}
+		for _, name := range s.Required {
+			if _, ok := val[name]; !ok {
+				errs = append(errs, fail("%s is required", name)...)
+			}
+		}
+
+		keys := make([]string, 0, len(val))
+		for key := range val {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if prop, ok := s.Properties[key]; ok {
+				errs = append(errs, prop.check(val[key], joinPath(path, key))...)
+				continue
+			}
+			if s.PropertyNames != nil {
+				errs = append(errs, s.PropertyNames.check(key, fmt.Sprintf("%s[%s]", path, key))...)
+			}
+			if s.AdditionalProperties != nil {
+				errs = append(errs, s.AdditionalProperties.check(val[key], fmt.Sprintf("%s[%s]", path, key))...)
+			}
+		}
+	}
+
+	return errs
+}
+
+func joinPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func hasType(v any, t string) bool {
+	switch val := v.(type) {
+	case nil:
+		return t == "null"
+	case bool:
+		return t == "boolean"
+	case string:
+		return t == "string"
+	case json.Number:
+		if t == "number" {
+			return true
+		}
+		_, err := val.Int64()
+		return t == "integer" && (err == nil || !strings.ContainsAny(val.String(), ".eE"))
+	case []any:
+		return t == "array"
+	case map[string]any:
+		return t == "object"
+	default:
+		return false
+	}
+}
+
+func typeName(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if hasType(val, "integer") {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func inEnum(v any, enum []any) bool {
+	encoded, _ := json.Marshal(v)
+	for _, option := range enum {
+		candidate, _ := json.Marshal(option)
+		if bytes.Equal(encoded, candidate) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSchemaValidate(t *testing.T) {
+	one, three, port := 1, 3, 65535.0
+	zero := 0.0
+
+	schema := &Schema{
+		Type:     "object",
+		Required: []string{"name"},
+		Properties: map[string]*Schema{
+			"name": {Type: "string", MinLength: &one, MaxLength: &three},
+			"mode": {Type: "string", Enum: []any{"fast", "slow"}},
+			"server": {Type: "object", Properties: map[string]*Schema{
+				"port": {Type: "integer", Minimum: &zero, Maximum: &port},
+			}},
+			"hosts": {Type: "array", MaxItems: &three, Items: &Schema{Type: "string", Pattern: `^[a-z]+$`}},
+			"limits": {
+				Type:                 "object",
+				PropertyNames:        &Schema{Type: "string", Pattern: `^[0-9]+$`},
+				AdditionalProperties: &Schema{Type: "number"},
+			},
+			"backup": {AnyOf: []*Schema{{Type: "string"}, {Type: "null"}}},
+		},
+	}
+
+	tests := []struct {
+		name string
+		doc  string
+		errs []string
+	}{
+		{"valid", `{"name":"ab","mode":"fast","server":{"port":80},"hosts":["a"],"limits":{"1":2.5},"backup":null}`, nil},
+		{"missing required", `{}`, []string{"<root>: name is required"}},
+		{"wrong type", `{"name":1}`, []string{"name: expected string, found integer"}},
+		{"too long", `{"name":"abcd"}`, []string{"name: must have at most 3 characters"}},
+		{"enum", `{"name":"a","mode":"medium"}`, []string{"mode: must be one of fast, slow"}},
+		{"nested maximum", `{"name":"a","server":{"port":70000}}`, []string{"server.port: must be at most 65535"}},
+		{"integer", `{"name":"a","server":{"port":1.5}}`, []string{"server.port: expected integer"}},
+		{"item pattern", `{"name":"a","hosts":["a","B"]}`, []string{"hosts[1]: must match"}},
+		{"too many items", `{"name":"a","hosts":["a","b","c","d"]}`, []string{"hosts: must have at most 3 elements"}},
+		{"map keys and values", `{"name":"a","limits":{"x":"y"}}`, []string{"limits[x]: must match", "limits[x]: expected number"}},
+		{"any of", `{"name":"a","backup":1}`, []string{"backup: expected string"}},
+		{"several problems", `{"name":"","mode":"x"}`, []string{"name: must have at least 1", "mode: must be one of"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := schema.Validate([]byte(test.doc))
+			if len(test.errs) == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected errors %v", test.errs)
+			}
+			for _, want := range test.errs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("got %q, want it to contain %q", err.Error(), want)
+				}
+			}
+		})
+	}
+}
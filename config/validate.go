@@ -0,0 +1,99 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Validator is implemented by config structs, at any level, that need to
+// check combinations of values, e.g. that MinWorkers <= MaxWorkers.
+type Validator interface {
+	Validate() error
+}
+
+var validatorType = reflect.TypeOf((*Validator)(nil)).Elem()
+
+// Validate calls Validate on every value inside v that implements Validator,
+// innermost first. Errors are prefixed with the path of the value that
+// rejected them, e.g. "Server: port is required when TLS is enabled".
+// Config files are validated when they are read and before they are written.
+func Validate(v any) error {
+	errs := runValidators(reflect.ValueOf(v), "")
+	if len(errs) == 0 {
+		return nil
+	}
+	return wrap(errors.Join(errs...))
+}
+
+func runValidators(v reflect.Value, path string) []error {
+	if !v.IsValid() {
+		return nil
+	}
+
+	errs := []error{}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return runValidators(v.Elem(), path)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Type().Field(i); field.IsExported() {
+				errs = append(errs, runValidators(v.Field(i), joinPath(path, field.Name))...)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			errs = append(errs, runValidators(v.Index(i), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+		})
+		for _, key := range keys {
+			errs = append(errs, runValidators(v.MapIndex(key), fmt.Sprintf("%s[%v]", path, key))...)
+		}
+	}
+
+	if validator, ok := AsValidator(v); ok {
+		if err := validator.Validate(); err != nil {
+			if path != "" {
+				err = fmt.Errorf("%s: %s", path, err.Error())
+			}
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// AsValidator returns the Validator for v, including one declared on *T when
+// v is not addressable.
+func AsValidator(v reflect.Value) (Validator, bool) {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil, false
+	}
+
+	if v.Type().Implements(validatorType) {
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			return nil, false
+		}
+		return v.Interface().(Validator), true
+	}
+
+	if reflect.PointerTo(v.Type()).Implements(validatorType) {
+		if v.CanAddr() {
+			return v.Addr().Interface().(Validator), true
+		}
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		return p.Interface().(Validator), true
+	}
+
+	return nil, false
+}
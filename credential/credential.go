@@ -21,6 +21,8 @@ func GetUserAuth(appName string) (Credential, error) {
 
 func GetUserAuthFresh(appName string) (Credential, error) {
 	var credential Credential
+
+	// the store is locked only while a change is saved, not while picking
 	manager, err := loadManager()
 	if err != nil {
 		return credential, err
@@ -31,11 +33,6 @@ func GetUserAuthFresh(appName string) (Credential, error) {
 		return credential, err
 	}
 
-	err = saveManager(manager)
-	if err != nil {
-		return credential, err
-	}
-
 	if cred == nil {
 		return credential, wrap(fmt.Errorf("did not select a credential"))
 	}
@@ -62,16 +59,10 @@ func GetDefaultUserAuth(appName string) (Credential, error) {
 
 func StandaloneManager() error {
 	manager, err := loadManager()
-
 	if err != nil {
 		return err
 	}
 
 	_, err = manager.pick("", true)
-	if err != nil {
-		return err
-	}
-
-	err = saveManager(manager)
 	return err
 }
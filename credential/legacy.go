@@ -18,7 +18,7 @@ func readCredentialFromLegacyFile(path string) (*Credential, error) {
 }
 
 func writeCredentialToLegacyFile(path string, cred Credential) error {
-	err := config.WriteConfigFile(path, cred)
+	err := config.WriteConfigFileWithConfig(path, cred, config.WriteConfig{Perm: 0o600, ForcePerm: true})
 	return err
 }
 
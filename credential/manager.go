@@ -1,7 +1,6 @@
 package credential
 
 import (
-	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -70,8 +69,20 @@ start:
 		}
 
 	case "n":
-		created := newCredential()
-		c.Credentials = append(c.Credentials, created)
+		created, err := newCredential()
+		if err != nil {
+			return nil, err
+		}
+		if created == nil {
+			goto start
+		}
+		err = c.change(func(manager *credentialmanager) bool {
+			manager.Credentials = append(manager.Credentials, created)
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
 		chosenCredential = created.Cred
 	case "r":
 		manager := command.NewManager(command.ManagerConfig{Searchable: true})
@@ -104,38 +115,45 @@ start:
 	return &chosenCredential, nil
 }
 
-func newCredential() *wrappedCredential {
-	fmt.Println("New Credential")
-	name := input.GetInput("Name")
-	description := input.GetInput("Description")
-	key := input.GetInput("Key")
-	secret := input.GetInput("Secret")
+func newCredential() (*wrappedCredential, error) {
+	form := input.NewForm("New Credential",
+		input.FormField{Key: "name", Label: "Name"},
+		input.FormField{Key: "description", Label: "Description"},
+		input.FormField{Key: "key", Label: "Key"},
+		input.FormField{Key: "secret", Label: "Secret", Kind: input.SecretField, Confirm: true},
+	)
+
+	values, err := form.Run()
+	if err != nil {
+		return nil, wrap(err)
+	}
+
+	if values == nil {
+		return nil, nil
+	}
+
 	return &wrappedCredential{
-		Name:        name,
-		Description: description,
+		Name:        values["name"].(string),
+		Description: values["description"].(string),
 		Cred: Credential{
-			Key:    key,
-			Secret: secret,
+			Key:    values["key"].(string),
+			Secret: values["secret"].(string),
 		},
-	}
+	}, nil
 }
 
 func removeCredential(manager *credentialmanager, cred *wrappedCredential) func() error {
 	return func() error {
-		newCreds := []*wrappedCredential{}
-
-		for _, wc := range manager.Credentials {
-			if wc != cred {
-				newCreds = append(newCreds, wc)
+		return manager.change(func(saved *credentialmanager) bool {
+			for i, wc := range saved.Credentials {
+				if *wc == *cred {
+					saved.Credentials = append(saved.Credentials[:i], saved.Credentials[i+1:]...)
+					return true
+				}
 			}
-		}
-		if len(newCreds)+1 != len(manager.Credentials) {
-			return fmt.Errorf("new list of length %d is not of expected length %d", len(newCreds), len(manager.Credentials)-1)
-		}
-
-		manager.Credentials = newCreds
-
-		return nil
+			// another process removed it already
+			return false
+		})
 	}
 }
 
@@ -146,8 +164,11 @@ func setPtr(p *Credential, v Credential) func() error {
 	}
 }
 
+// configPath is replaced in tests to keep the store out of the user's config
+var configPath = config.GetConfigPath
+
 func centralCredLocation() (string, error) {
-	cpath, err := config.GetConfigPath("gct-credmanager")
+	cpath, err := configPath("gct-credmanager")
 	if err != nil {
 		if os.IsNotExist(err) {
 			return "", nil
@@ -158,6 +179,45 @@ func centralCredLocation() (string, error) {
 	return filepath.Join(cpath, "credstore"), nil
 }
 
+// updateManager loads the credential store, lets update change it and saves
+// it when update reports a change, holding the store's lock so that changes
+// from other processes are not lost. It returns the store as saved.
+func updateManager(update func(manager *credentialmanager) bool) (*credentialmanager, error) {
+	path, err := centralCredLocation()
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := config.LockFile(path)
+	if err != nil {
+		return nil, wrap(err)
+	}
+	defer unlock()
+
+	manager, err := loadManager()
+	if err != nil {
+		return nil, err
+	}
+
+	if !update(manager) {
+		return manager, nil
+	}
+
+	return manager, saveManager(manager)
+}
+
+// change applies update to the saved store, not to c, which may be out of
+// date, and then refreshes c.
+func (c *credentialmanager) change(update func(manager *credentialmanager) bool) error {
+	manager, err := updateManager(update)
+	if err != nil {
+		return err
+	}
+
+	c.Credentials = manager.Credentials
+	return nil
+}
+
 func saveManager(manager *credentialmanager) error {
 	path, err := centralCredLocation()
 	if err != nil {
@@ -171,14 +231,13 @@ func saveManager(manager *credentialmanager) error {
 		return wrap(err)
 	}
 
-	f, err := os.Create(path)
+	data, err := io.ReadAll(src)
 	if err != nil {
 		return wrap(err)
 	}
-	defer f.Close()
 
-	io.Copy(f, src)
-	return nil
+	// stores written before permissions were set may still be readable by others
+	return wrap(config.WriteFileAtomicWithConfig(path, data, config.AtomicConfig{Perm: 0o600, ForcePerm: true}))
 }
 
 func loadManager() (*credentialmanager, error) {
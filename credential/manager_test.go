@@ -0,0 +1,130 @@
+//go:build !windows
+
+package credential
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/lspaccatrosi16/go-cli-tools/termtest"
+)
+
+func useTempStore(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	old := configPath
+	configPath = func(string) (string, error) {
+		return dir, nil
+	}
+	t.Cleanup(func() {
+		configPath = old
+	})
+
+	path, err := centralCredLocation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestStandaloneManagerAdd(t *testing.T) {
+	path := useTempStore(t)
+
+	s := termtest.New(t, termtest.Options{Height: 40, Timeout: 15 * time.Second})
+
+	var err error
+	s.Run(func() {
+		err = StandaloneManager()
+	})
+
+	s.Expect("Select Preference")
+	s.Press("down", "down", "enter")
+
+	s.Expect("Name?")
+	s.Type("work\r")
+	s.Expect("Description?")
+	s.Type("work account\r")
+	s.Expect("Key?")
+	s.Type("key-1\r")
+	s.Expect("Secret?")
+	s.Type("hunter2\r")
+	s.Expect("Confirm secret?")
+	s.Type("hunter2\r")
+
+	s.Expect("Review")
+	s.Press("down", "down", "down", "down", "enter")
+
+	s.Expect("Make this the default credential")
+	s.Press("down", "enter")
+
+	s.Expect("Select Preference")
+	s.Press("enter")
+	s.Wait()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager, err := loadManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manager.Credentials) != 1 {
+		t.Fatalf("got %d credentials, want 1", len(manager.Credentials))
+	}
+
+	want := wrappedCredential{Name: "work", Description: "work account", Cred: Credential{Key: "key-1", Secret: "hunter2"}}
+	if *manager.Credentials[0] != want {
+		t.Errorf("got %+v, want %+v", *manager.Credentials[0], want)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("store has permissions %o, want 600", perm)
+	}
+}
+
+func TestStandaloneManagerRemove(t *testing.T) {
+	useTempStore(t)
+
+	err := saveManager(&credentialmanager{Credentials: []*wrappedCredential{
+		{Name: "home", Cred: Credential{Key: "a", Secret: "b"}},
+		{Name: "work", Cred: Credential{Key: "c", Secret: "d"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := termtest.New(t, termtest.Options{Height: 40, Timeout: 15 * time.Second})
+
+	s.Run(func() {
+		err = StandaloneManager()
+	})
+
+	s.Expect("Select Preference")
+	s.Press("down", "down", "down", "enter")
+	s.Expect("Select the command to execute")
+	s.Type("work")
+	s.Press("enter")
+
+	s.Expect("Select Preference")
+	s.Press("enter")
+	s.Wait()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager, err := loadManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manager.Credentials) != 1 || manager.Credentials[0].Name != "home" {
+		t.Errorf("got %+v, want only home", manager.Credentials)
+	}
+}
@@ -8,10 +8,11 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/lspaccatrosi16/go-cli-tools/progress"
 	"github.com/mandelsoft/vfs/pkg/vfs"
 )
 
-func crawlAndAdd(base string, zipBase string, fs vfs.FileSystem, zipWriter *zip.Writer) error {
+func crawlAndAdd(base string, zipBase string, fs vfs.FileSystem, zipWriter *zip.Writer, bar *progress.Bar) error {
 	files, err := vfs.ReadDir(fs, base)
 	if err != nil {
 		return err
@@ -21,7 +22,7 @@ func crawlAndAdd(base string, zipBase string, fs vfs.FileSystem, zipWriter *zip.
 		if file.IsDir() {
 			newBase := filepath.Join(base, file.Name())
 			newZipBase := filepath.Join(zipBase, file.Name())
-			err = crawlAndAdd(newBase, newZipBase, fs, zipWriter)
+			err = crawlAndAdd(newBase, newZipBase, fs, zipWriter, bar)
 			if err != nil {
 				return err
 			}
@@ -38,18 +39,58 @@ func crawlAndAdd(base string, zipBase string, fs vfs.FileSystem, zipWriter *zip.
 				return err
 			}
 			io.Copy(dst, src)
+			if bar != nil {
+				bar.Add(1)
+			}
 		}
 	}
 	return nil
 }
 
+func countFiles(base string, fs vfs.FileSystem) (int, error) {
+	files, err := vfs.ReadDir(fs, base)
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, file := range files {
+		if file.IsDir() {
+			n, err := countFiles(filepath.Join(base, file.Name()), fs)
+			if err != nil {
+				return 0, err
+			}
+			total += n
+		} else {
+			total++
+		}
+	}
+	return total, nil
+}
+
 func ZipFolder(startDir string, fs vfs.FileSystem) (*[]byte, error) {
+	return zipFolder(startDir, fs, false)
+}
+
+func ZipFolderWithProgress(startDir string, fs vfs.FileSystem) (*[]byte, error) {
+	return zipFolder(startDir, fs, true)
+}
+
+func zipFolder(startDir string, fs vfs.FileSystem, showProgress bool) (*[]byte, error) {
 	if exists, err := vfs.DirExists(fs, startDir); err != nil || !exists {
 		return nil, fmt.Errorf("path %s does not exist or is not a directory", startDir)
 	}
+	var bar *progress.Bar
+	if showProgress {
+		total, err := countFiles(startDir, fs)
+		if err != nil {
+			return nil, err
+		}
+		bar = progress.NewBar(startDir, int64(total), progress.Items)
+		defer bar.Finish()
+	}
 	output := bytes.NewBuffer([]byte{})
 	zipWriter := zip.NewWriter(output)
-	err := crawlAndAdd(startDir, "", fs, zipWriter)
+	err := crawlAndAdd(startDir, "", fs, zipWriter, bar)
 	if err != nil {
 		return nil, err
 	}
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"cloud.google.com/go/firestore"
+	"github.com/lspaccatrosi16/go-cli-tools/progress"
 	"google.golang.org/api/iterator"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -152,6 +153,14 @@ func (f *FirestoreClient) DeleteDoc(path string) error {
 }
 
 func (f *FirestoreClient) DeleteCol(path string) error {
+	return f.deleteCol(path, false)
+}
+
+func (f *FirestoreClient) DeleteColWithProgress(path string) error {
+	return f.deleteCol(path, true)
+}
+
+func (f *FirestoreClient) deleteCol(path string, showProgress bool) error {
 	colRef, err := f.parseColPath(path)
 
 	if err != nil {
@@ -164,12 +173,22 @@ func (f *FirestoreClient) DeleteCol(path string) error {
 		return wrapFirestore(err)
 	}
 
+	var bar *progress.Bar
+	if showProgress {
+		bar = progress.NewBar(path, int64(len(docs)), progress.Items)
+		defer bar.Finish()
+	}
+
 	for _, doc := range docs {
 		_, err = doc.Ref.Delete(f.app.ctx)
 
 		if err != nil {
 			return wrapFirestore(err)
 		}
+
+		if bar != nil {
+			bar.Add(1)
+		}
 	}
 
 	return nil
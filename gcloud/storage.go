@@ -8,6 +8,7 @@ import (
 
 	s2 "cloud.google.com/go/storage"
 	s1 "firebase.google.com/go/v4/storage"
+	"github.com/lspaccatrosi16/go-cli-tools/progress"
 	"google.golang.org/api/iterator"
 )
 
@@ -36,11 +37,27 @@ func (b *Bucket) GetFile(key string) ([]byte, error) {
 	return buffer.Bytes(), nil
 }
 
+// progressChunkSize is the smallest chunk the writer allows. It buffers at
+// most one chunk ahead of what has been sent, so the bar follows the upload
+// itself, with the last chunk sent while the writer is closed.
+const progressChunkSize = 256 * 1024
+
 func (b *Bucket) UploadFile(key string, contents []byte) error {
-	buf := bytes.NewReader(contents)
+	wc := b.Bucket.Object(key).NewWriter(b.ctx)
+	return b.upload(wc, bytes.NewReader(contents))
+}
+
+func (b *Bucket) UploadFileWithProgress(key string, contents []byte) error {
+	bar := progress.NewBar(key, int64(len(contents)), progress.Bytes)
+	defer bar.Finish()
 
 	wc := b.Bucket.Object(key).NewWriter(b.ctx)
+	wc.ChunkSize = progressChunkSize
+
+	return b.upload(wc, bar.Reader(bytes.NewReader(contents)))
+}
 
+func (b *Bucket) upload(wc *s2.Writer, buf io.Reader) error {
 	io.Copy(wc, buf)
 
 	err := wc.Close()
@@ -0,0 +1,150 @@
+package input
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// editorScissors separates the answer from the instructions below it, like
+// git's commit message template, so that lines of the answer starting with #
+// are kept.
+const editorScissors = "# ------------------------ >8 ------------------------"
+
+func GetEditorInput(label string, initial string, ext string) (string, error) {
+	stubValidator := func(str string) error {
+		return nil
+	}
+
+	return GetValidatedEditorInput(label, initial, ext, stubValidator)
+}
+
+func GetValidatedEditorInput(label string, initial string, ext string, validator func(str string) error) (string, error) {
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+
+	f, err := os.CreateTemp("", "input-*"+ext)
+	if err != nil {
+		return "", wrap(err)
+	}
+	path := f.Name()
+	f.Close()
+	defer os.Remove(path)
+
+	content := initial
+	var validationError error
+
+	for {
+		err = os.WriteFile(path, []byte(editorTemplate(label, content, validationError)), 0o600)
+		if err != nil {
+			return "", wrap(err)
+		}
+
+		err = launchEditor(path)
+		if err != nil {
+			return "", wrap(err)
+		}
+
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return "", wrap(err)
+		}
+
+		content = stripComments(string(raw))
+		validationError = validator(content)
+
+		if validationError == nil {
+			break
+		}
+
+		// an emptied file is the only way out of the editor loop
+		if content == "" {
+			return "", wrap(validationError)
+		}
+	}
+
+	lines := strings.Count(content, "\n") + 1
+	if content == "" {
+		lines = 0
+	}
+	fmt.Printf("%s: (%d lines)\n", label, lines)
+
+	return content, nil
+}
+
+func ValidateJSON(str string) error {
+	var v any
+	err := json.Unmarshal([]byte(str), &v)
+	if err != nil {
+		return fmt.Errorf("invalid json: %s", err.Error())
+	}
+	return nil
+}
+
+func editorTemplate(label string, content string, validationError error) string {
+	buf := bytes.NewBuffer(nil)
+
+	fmt.Fprintln(buf, content)
+	fmt.Fprintln(buf, editorScissors)
+	fmt.Fprintf(buf, "# %s\n", label)
+	fmt.Fprintln(buf, "# Do not modify or remove the line above. Everything below it is ignored.")
+	fmt.Fprintln(buf, "# Save and close the editor to continue, or clear the text above to abort.")
+	if validationError != nil {
+		fmt.Fprintf(buf, "# ERROR: %s\n", validationError.Error())
+	}
+
+	return buf.String()
+}
+
+// stripComments drops the instructions below the scissors line and the blank
+// lines around the answer, keeping the indentation of its first line.
+func stripComments(str string) string {
+	kept := []string{}
+	for _, line := range strings.Split(str, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == editorScissors {
+			break
+		}
+		kept = append(kept, line)
+	}
+
+	for len(kept) > 0 && strings.TrimSpace(kept[0]) == "" {
+		kept = kept[1:]
+	}
+
+	return strings.TrimRight(strings.Join(kept, "\n"), " \t\n")
+}
+
+func getEditor() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return fields
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+	return []string{"vi"}
+}
+
+func launchEditor(path string) error {
+	editor := getEditor()
+
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("editor %s failed: %s", editor[0], err.Error())
+	}
+
+	return nil
+}
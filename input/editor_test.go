@@ -0,0 +1,39 @@
+package input
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEditorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"plain", "hello\nworld", "hello\nworld"},
+		{"markdown heading", "# Title\n\nbody", "# Title\n\nbody"},
+		{"indented hash", "  # comment in code\nx = 1", "  # comment in code\nx = 1"},
+		{"blank lines around", "\n\n  indented\n\n", "  indented"},
+		{"empty", "", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, err := range []error{nil, errors.New("bad")} {
+				got := stripComments(editorTemplate("Label", test.content, err))
+				if got != test.want {
+					t.Fatalf("got %q, want %q", got, test.want)
+				}
+			}
+		})
+	}
+}
+
+func TestStripCommentsWithoutScissors(t *testing.T) {
+	// an answer whose scissors line was removed is kept whole
+	got := stripComments("# heading\ntext\r\n")
+	if got != "# heading\ntext" {
+		t.Fatalf("got %q", got)
+	}
+}
@@ -0,0 +1,257 @@
+package input
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type FieldKind int
+
+const (
+	TextField FieldKind = iota
+	SecretField
+	SelectField
+	ConfirmField
+	NumberField
+)
+
+type FormField struct {
+	Key   string
+	Label string
+	Kind  FieldKind
+	// Default is the answer offered first, and the current answer is offered
+	// when a field is changed from the review screen. Secret fields are always
+	// typed again.
+	Default   any
+	Options   []SelectOption
+	Validator func(str string) error
+	Confirm   bool
+	Min       *int
+	Max       *int
+}
+
+type Form struct {
+	Title  string
+	Fields []FormField
+}
+
+func NewForm(title string, fields ...FormField) *Form {
+	return &Form{Title: title, Fields: fields}
+}
+
+// Run asks for every field in turn and then shows a review screen where any
+// answer can be changed. A nil map is returned if the form is cancelled.
+func (f *Form) Run() (map[string]any, error) {
+	values := map[string]any{}
+
+	for _, field := range f.Fields {
+		if field.Default != nil {
+			values[field.Key] = field.Default
+		}
+	}
+
+	if f.Title != "" {
+		fmt.Println(f.Title)
+	}
+
+	for _, field := range f.Fields {
+		v, err := askField(field, values[field.Key])
+		if err != nil {
+			return nil, wrap(err)
+		}
+		values[field.Key] = v
+	}
+
+	for {
+		opts := []SelectOption{}
+		for _, field := range f.Fields {
+			opts = append(opts, SelectOption{
+				Name:  fmt.Sprintf("%s: %s", field.Label, displayField(field, values[field.Key])),
+				Value: field.Key,
+			})
+		}
+		opts = append(opts, SelectOption{Name: "Submit", Value: "submit"}, SelectOption{Name: "Cancel", Value: "cancel"})
+
+		selected, err := GetSelection("Review (select a field to change it)", opts)
+		if err != nil {
+			return nil, wrap(err)
+		}
+
+		switch selected {
+		case "submit":
+			return values, nil
+		case "cancel":
+			return nil, nil
+		}
+
+		for _, field := range f.Fields {
+			if field.Key != selected {
+				continue
+			}
+
+			v, err := reviewField(field, values[field.Key])
+			if err != nil {
+				return nil, wrap(err)
+			}
+			values[field.Key] = v
+		}
+	}
+}
+
+// reviewField asks for a field again from the review screen. An empty answer
+// keeps a text field as it is, so clearing it is offered separately.
+func reviewField(field FormField, current any) (any, error) {
+	if s, ok := current.(string); field.Kind != TextField || !ok || s == "" {
+		return askField(field, current)
+	}
+
+	opts := []SelectOption{
+		{Name: "Change", Value: "change"},
+		{Name: "Clear", Value: "clear"},
+		{Name: "Keep", Value: "keep"},
+	}
+
+	for {
+		action, err := GetSelection(field.Label, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		switch action {
+		case "keep":
+			return current, nil
+		case "change":
+			return askField(field, current)
+		}
+
+		if field.Validator != nil {
+			if err := field.Validator(""); err != nil {
+				fmt.Printf("ERROR: %s\n", err.Error())
+				continue
+			}
+		}
+		return "", nil
+	}
+}
+
+// Fill runs the form and copies the answers into the struct pointed to by dst,
+// matching keys against a `form` tag or the field name.
+func (f *Form) Fill(dst any) (bool, error) {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return false, wrap(fmt.Errorf("fill destination must be a pointer to a struct, not %T", dst))
+	}
+
+	values, err := f.Run()
+	if err != nil || values == nil {
+		return false, err
+	}
+
+	s := v.Elem()
+	t := s.Type()
+
+	for key, value := range values {
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			name := sf.Tag.Get("form")
+			if name == "" {
+				name = sf.Name
+			}
+			if !strings.EqualFold(name, key) || !sf.IsExported() {
+				continue
+			}
+
+			// Convert would turn a number into the character with that code
+			rv := reflect.ValueOf(value)
+			if !rv.Type().ConvertibleTo(sf.Type) || (sf.Type.Kind() == reflect.String && rv.Kind() != reflect.String) {
+				return false, wrap(fmt.Errorf("cannot assign %T to field %s of type %s", value, sf.Name, sf.Type))
+			}
+			s.Field(i).Set(rv.Convert(sf.Type))
+		}
+	}
+
+	return true, nil
+}
+
+func askField(field FormField, current any) (any, error) {
+	switch field.Kind {
+	case TextField:
+		validator := field.Validator
+		if validator == nil {
+			validator = func(string) error {
+				return nil
+			}
+		}
+
+		parse := func(str string) (string, error) {
+			return str, validator(str)
+		}
+		format := func(str string) string {
+			return str
+		}
+
+		var def *string
+		if s, ok := current.(string); ok && s != "" {
+			def = &s
+		}
+
+		return GetParsedInput(field.Label, def, format, parse)
+	case SecretField:
+		return GetSecretInput(field.Label, SecretConfig{Mask: '*', Confirm: field.Confirm, Validator: field.Validator})
+	case SelectField:
+		def, _ := current.(string)
+		v, _, err := GetSelectionWithConfig(field.Label, field.Options, SelectConfig{Default: def})
+		return v, err
+	case ConfirmField:
+		def := ""
+		if b, ok := current.(bool); ok {
+			def = "n"
+			if b {
+				def = "y"
+			}
+		}
+		return confirmSelection(field.Label, def)
+	case NumberField:
+		config := RangeConfig[int]{Min: field.Min, Max: field.Max}
+		if n, ok := current.(int); ok {
+			config.Default = &n
+		}
+		return GetInt(field.Label, config)
+	}
+
+	return nil, fmt.Errorf("unknown field kind %d", field.Kind)
+}
+
+func displayField(field FormField, value any) string {
+	switch field.Kind {
+	case SecretField:
+		if value == nil || value == "" {
+			return ""
+		}
+		return secretPlaceholder
+	case SelectField:
+		for _, opt := range field.Options {
+			if opt.Value == value {
+				return opt.Name
+			}
+		}
+	case ConfirmField:
+		if b, ok := value.(bool); ok {
+			if b {
+				return "Yes"
+			}
+			return "No"
+		}
+	case NumberField:
+		if n, ok := value.(int); ok {
+			return strconv.Itoa(n)
+		}
+	}
+
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,102 @@
+package input
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testForm() *Form {
+	return NewForm("",
+		FormField{Key: "name", Label: "Name"},
+		FormField{Key: "age", Label: "Age", Kind: NumberField},
+	)
+}
+
+func TestFormReview(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  map[string]any
+	}{
+		{"submit", "bob\n30\n3\n", map[string]any{"name": "bob", "age": 30}},
+		{"keep on empty answer", "bob\n30\n1\n1\n\n3\n", map[string]any{"name": "bob", "age": 30}},
+		{"change", "bob\n30\n1\n1\nann\n3\n", map[string]any{"name": "ann", "age": 30}},
+		{"clear", "bob\n30\n1\n2\n3\n", map[string]any{"name": "", "age": 30}},
+		{"keep", "bob\n30\n1\n3\n3\n", map[string]any{"name": "bob", "age": 30}},
+		{"cancel", "bob\n30\n4\n", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			withStdin(t, test.input)
+
+			got, err := testForm().Run()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFormFill(t *testing.T) {
+	withStdin(t, "bob\n30\n3\n")
+	var ok struct {
+		Name string
+		Age  int64 `form:"age"`
+	}
+	if filled, err := testForm().Fill(&ok); err != nil || !filled {
+		t.Fatalf("got %v, %v", filled, err)
+	}
+	if ok.Name != "bob" || ok.Age != 30 {
+		t.Fatalf("got %+v", ok)
+	}
+
+	withStdin(t, "bob\n65\n3\n")
+	var wrong struct {
+		Name string
+		Age  string
+	}
+	if _, err := testForm().Fill(&wrong); err == nil {
+		t.Fatalf("expected an error assigning a number to a string, got %+v", wrong)
+	}
+}
+
+func TestFormSelectDefaults(t *testing.T) {
+	form := func() *Form {
+		return NewForm("",
+			FormField{Key: "color", Label: "Color", Kind: SelectField, Default: "green", Options: []SelectOption{
+				{Name: "Red", Value: "red"},
+				{Name: "Green", Value: "green"},
+			}},
+			FormField{Key: "ok", Label: "OK", Kind: ConfirmField, Default: false},
+		)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  map[string]any
+	}{
+		{"defaults", "\n\n3\n", map[string]any{"color": "green", "ok": false}},
+		{"answers", "1\n1\n3\n", map[string]any{"color": "red", "ok": true}},
+		{"review keeps current", "1\n1\n1\n\n2\n\n3\n", map[string]any{"color": "red", "ok": true}},
+		{"review changes", "1\n1\n2\n2\n3\n", map[string]any{"color": "red", "ok": false}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			withStdin(t, test.input)
+
+			got, err := form().Run()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package input
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	fuzzyMatchScore       = 16
+	fuzzyConsecutiveBonus = 15
+	fuzzyBoundaryBonus    = 10
+	fuzzyPrefixBonus      = 15
+	fuzzyGapPenalty       = 1
+)
+
+type fuzzyResult struct {
+	index     int
+	score     int
+	positions []int
+}
+
+// fuzzyMatch finds the best scoring way to match pattern as a case-insensitive
+// subsequence of str, returning the rune positions in str that were matched.
+func fuzzyMatch(pattern string, str string) (int, []int, bool) {
+	p := []rune(strings.ToLower(pattern))
+	s := []rune(str)
+	lower := []rune(strings.ToLower(str))
+
+	if len(p) == 0 {
+		return 0, nil, true
+	}
+	if len(p) > len(s) {
+		return 0, nil, false
+	}
+
+	const none = -1 << 31
+
+	score := make([][]int, len(p))
+	from := make([][]int, len(p))
+
+	for i := range p {
+		score[i] = make([]int, len(s))
+		from[i] = make([]int, len(s))
+
+		for j := range s {
+			score[i][j] = none
+			if lower[j] != p[i] {
+				continue
+			}
+
+			bonus := fuzzyMatchScore
+			if j == 0 {
+				bonus += fuzzyPrefixBonus
+			} else if isWordBoundary(s[j-1], s[j]) {
+				bonus += fuzzyBoundaryBonus
+			}
+
+			if i == 0 {
+				score[i][j] = bonus - j*fuzzyGapPenalty
+				from[i][j] = -1
+				continue
+			}
+
+			for k := i - 1; k < j; k++ {
+				if score[i-1][k] == none {
+					continue
+				}
+
+				candidate := score[i-1][k] + bonus
+				if k == j-1 {
+					candidate += fuzzyConsecutiveBonus
+				} else {
+					candidate -= (j - k - 1) * fuzzyGapPenalty
+				}
+
+				if candidate > score[i][j] {
+					score[i][j] = candidate
+					from[i][j] = k
+				}
+			}
+		}
+	}
+
+	last := len(p) - 1
+	best := -1
+	for j := range s {
+		if score[last][j] != none && (best == -1 || score[last][j] > score[last][best]) {
+			best = j
+		}
+	}
+
+	if best == -1 {
+		return 0, nil, false
+	}
+
+	positions := make([]int, len(p))
+	for i, j := last, best; i >= 0; i-- {
+		positions[i] = j
+		j = from[i][j]
+	}
+
+	return score[last][best], positions, true
+}
+
+func isWordBoundary(prev rune, cur rune) bool {
+	switch prev {
+	case ' ', '-', '_', '/', '.', ':':
+		return true
+	}
+	return unicode.IsLower(prev) && unicode.IsUpper(cur)
+}
+
+func rankOptions(pattern string, items []SelectOption) []fuzzyResult {
+	results := []fuzzyResult{}
+
+	for i, item := range items {
+		score, positions, ok := fuzzyMatch(pattern, item.Name)
+		if ok {
+			results = append(results, fuzzyResult{index: i, score: score, positions: positions})
+		}
+	}
+
+	if pattern != "" {
+		sort.SliceStable(results, func(i, j int) bool {
+			return results[i].score > results[j].score
+		})
+	}
+
+	return results
+}
+
+func highlightMatches(str string, positions []int) string {
+	if len(positions) == 0 {
+		return str
+	}
+
+	matched := map[int]bool{}
+	for _, p := range positions {
+		matched[p] = true
+	}
+
+	var b strings.Builder
+	for i, r := range []rune(str) {
+		if matched[i] {
+			b.WriteString("\x1b[1;4m")
+			b.WriteRune(r)
+			b.WriteString("\x1b[22;24m")
+		} else {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
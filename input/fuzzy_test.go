@@ -0,0 +1,81 @@
+package input
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		str       string
+		ok        bool
+		positions []int
+	}{
+		{"", "anything", true, nil},
+		{"abc", "abc", true, []int{0, 1, 2}},
+		{"ABC", "abc", true, []int{0, 1, 2}},
+		{"ac", "abc", true, []int{0, 2}},
+		{"ca", "abc", false, nil},
+		{"abcd", "abc", false, nil},
+		{"sp", "server-port", true, []int{0, 7}},
+		{"sp", "ServerPort", true, []int{0, 6}},
+		{"ü", "Über", true, []int{0}},
+	}
+
+	for _, test := range tests {
+		_, positions, ok := fuzzyMatch(test.pattern, test.str)
+		if ok != test.ok {
+			t.Errorf("%q in %q: got ok %v, want %v", test.pattern, test.str, ok, test.ok)
+			continue
+		}
+		if ok && !reflect.DeepEqual(positions, test.positions) {
+			t.Errorf("%q in %q: got positions %v, want %v", test.pattern, test.str, positions, test.positions)
+		}
+	}
+}
+
+func TestRankOptions(t *testing.T) {
+	names := func(items ...string) []SelectOption {
+		out := []SelectOption{}
+		for _, item := range items {
+			out = append(out, SelectOption{Name: item})
+		}
+		return out
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		items   []SelectOption
+		want    []int
+	}{
+		{"empty keeps order", "", names("b", "a", "c"), []int{0, 1, 2}},
+		{"drops non matches", "x", names("b", "x", "c"), []int{1}},
+		{"prefix first", "con", names("icon", "config"), []int{1, 0}},
+		{"consecutive over scattered", "log", names("list of groups", "logging"), []int{1, 0}},
+		{"word boundary over middle", "p", names("stop", "set-port"), []int{1, 0}},
+		{"ties keep order", "a", names("a", "a"), []int{0, 1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := []int{}
+			for _, result := range rankOptions(test.pattern, test.items) {
+				got = append(got, result.index)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestHighlightMatches(t *testing.T) {
+	if got := highlightMatches("abc", nil); got != "abc" {
+		t.Fatalf("got %q", got)
+	}
+	if got := highlightMatches("abc", []int{1}); got != "a\x1b[1;4mb\x1b[22;24mc" {
+		t.Fatalf("got %q", got)
+	}
+}
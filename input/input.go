@@ -1,12 +1,13 @@
 package input
 
 import (
-	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
 	"github.com/lspaccatrosi16/go-cli-tools/internal/pkgError"
+	"github.com/lspaccatrosi16/go-cli-tools/internal/tty"
 	"github.com/manifoldco/promptui"
 )
 
@@ -15,48 +16,47 @@ const LINE_CLEAR = "\x1b[2K"
 
 var wrap = pkgError.WrapErrorFactory("input")
 
-func getInputTemplate() *promptui.SelectTemplates {
-	return &promptui.SelectTemplates{
-		Active:   "{{ .Name | green }}",
-		Inactive: "{{ .Name }}",
-		Selected: "{{ .Name }}",
-	}
-}
-
-func makeSelector(label string, items []SelectOption) promptui.Select {
-	template := getInputTemplate()
-
+func makeSelector(label string, items []SelectOption, config SelectConfig) promptui.Select {
 	prompt := promptui.Select{
 		Label:        label,
-		Templates:    template,
+		Templates:    GetTheme().selectTemplates(),
 		Items:        items,
 		HideSelected: true,
+		CursorPos:    defaultIndex(items, config.Default),
+	}
+
+	if config.PageSize > 0 {
+		prompt.Size = config.PageSize
 	}
 
 	return prompt
 }
 
-func makeSearchableSelector(label string, items []SelectOption) promptui.Select {
-	template := getInputTemplate()
-
-	searcher := func(input string, index int) bool {
-		item := items[index]
-		inputted := strings.ToLower(input)
-		name := strings.ToLower(item.Name)
-		return strings.Contains(name, inputted)
+func defaultIndex(items []SelectOption, value string) int {
+	first := -1
+	for i, item := range items {
+		if item.Disabled {
+			continue
+		}
+		if item.Value == value {
+			return i
+		}
+		if first == -1 {
+			first = i
+		}
 	}
 
-	prompt := promptui.Select{
-		Label:        label,
-		Items:        items,
-		Templates:    template,
-		Searcher:     searcher,
-		IsVimMode:    false,
-		HideSelected: true,
+	if first == -1 {
+		return 0
 	}
+	return first
+}
 
-	return prompt
-
+func disabledError(item SelectOption) error {
+	if item.DisabledReason != "" {
+		return fmt.Errorf("%s is unavailable: %s", item.Name, item.DisabledReason)
+	}
+	return fmt.Errorf("%s is unavailable", item.Name)
 }
 
 func GetSelection(label string, items []SelectOption) (string, error) {
@@ -70,15 +70,50 @@ func GetSelection(label string, items []SelectOption) (string, error) {
 }
 
 func GetSelectionIdx(label string, items []SelectOption) (string, int, error) {
-	prompt := makeSelector(label, items)
+	return GetSelectionWithConfig(label, items, SelectConfig{})
+}
 
-	i, _, err := prompt.Run()
+func GetSelectionWithConfig(label string, items []SelectOption, config SelectConfig) (string, int, error) {
+	if !tty.Interactive() {
+		i, err := plainSelect(label, items, config, false)
+		if err != nil {
+			return "", -1, wrap(err)
+		}
+		return items[i].Value, i, nil
+	}
 
-	if err != nil {
-		return "", -1, wrap(err)
+	prompt := makeSelector(label, items, config)
+	cursor := prompt.CursorPos
+
+	size := prompt.Size
+	if size == 0 {
+		size = 5
 	}
 
-	return items[i].Value, i, nil
+	for {
+		// promptui applies the scroll after the cursor so keep the cursor in view
+		scroll := cursor - size + 1
+		if scroll < 0 {
+			scroll = 0
+		}
+
+		output := &promptOutput{}
+		prompt.Stdout = output
+		i, _, err := prompt.RunCursorAt(cursor, scroll)
+		output.Close()
+
+		if err != nil {
+			return "", -1, wrap(err)
+		}
+
+		if items[i].Disabled {
+			fmt.Printf("ERROR: %s\n", disabledError(items[i]).Error())
+			cursor = i
+			continue
+		}
+
+		return items[i].Value, i, nil
+	}
 }
 
 func GetSearchableSelection(label string, items []SelectOption) (string, error) {
@@ -92,9 +127,11 @@ func GetSearchableSelection(label string, items []SelectOption) (string, error)
 }
 
 func GetSearchableSelectionIdx(label string, items []SelectOption) (string, int, error) {
-	prompt := makeSearchableSelector(label, items)
+	return GetSearchableSelectionWithConfig(label, items, SelectConfig{})
+}
 
-	i, _, err := prompt.Run()
+func GetSearchableSelectionWithConfig(label string, items []SelectOption, config SelectConfig) (string, int, error) {
+	i, err := runSearchableSelect(label, items, config)
 
 	if err != nil {
 		return "", -1, wrap(err)
@@ -104,12 +141,18 @@ func GetSearchableSelectionIdx(label string, items []SelectOption) (string, int,
 }
 
 func GetConfirmSelection(label string) (bool, error) {
+	return confirmSelection(label, "")
+}
+
+// confirmSelection is GetConfirmSelection with the answer def, "y" or "n",
+// selected first. Piped input takes an empty line as def.
+func confirmSelection(label string, def string) (bool, error) {
 	items := []SelectOption{
 		{Name: "Yes", Value: "y"},
 		{Name: "No", Value: "n"},
 	}
 
-	val, err := GetSelection(label, items)
+	val, _, err := GetSelectionWithConfig(label, items, SelectConfig{Default: def})
 
 	if err != nil {
 		return false, wrap(err)
@@ -127,56 +170,85 @@ func GetInput(label string) string {
 }
 
 func GetValidatedInput(label string, validator func(str string) error) string {
+	// the answer is returned as before when stdin ends
+	result, _ := GetValidatedInputErr(label, validator)
+	return result
+}
+
+// GetValidatedInputErr is GetValidatedInput, but returns io.EOF when stdin
+// ends before a valid answer is given, together with the validation error if
+// there was one.
+func GetValidatedInputErr(label string, validator func(str string) error) (string, error) {
+	display := func(str string) string {
+		return str
+	}
+
+	return getValidatedInput(label, validator, display)
+}
+
+func getValidatedInput(label string, validator func(str string) error, display func(str string) string) (string, error) {
 	var result string
 
-	scanner := bufio.NewScanner(os.Stdin)
+	interactive := tty.Interactive()
 	fmt.Printf("%s? ", label)
 	linesUsed := 0
 
 	for {
-		scanner.Scan()
+		line, err := tty.Stdin().ReadString('\n')
 		linesUsed++
 
-		result = scanner.Text()
+		result = strings.TrimRight(line, "\r\n")
+
+		if !tty.StdinIsTerminal() {
+			fmt.Println(result)
+		}
+
+		if err != nil && line == "" {
+			fmt.Println()
+			return result, wrap(io.EOF)
+		}
 
 		validationError := validator(result)
 
 		if validationError != nil {
+			if err != nil {
+				// stdin is closed so there is nothing left to re-prompt with
+				fmt.Println()
+				return result, wrap(fmt.Errorf("%w: %s", io.EOF, validationError.Error()))
+			}
 			fmt.Printf("ERROR: %s\n", validationError.Error())
 			linesUsed++
+			if !interactive {
+				fmt.Printf("%s? ", label)
+			}
 			continue
 		}
 		break
 	}
 
+	if !interactive {
+		return result, nil
+	}
+
 	for i := 0; i < linesUsed; i++ {
 		fmt.Print(LINE_UP)
 		fmt.Print(LINE_CLEAR)
 	}
 
-	fmt.Printf("%s: %s\n", label, result)
+	fmt.Printf("%s: %s\n", label, display(result))
 
-	return result
+	return result, nil
 }
 
 func GetFileInput(question string) ([]byte, error) {
-	var fileContents []byte
-
-	for {
-		path := GetInput(question)
-		fc, err := os.ReadFile(path)
+	path, err := GetPathInput(question, PathConfig{Kind: PathFile, MustExist: true})
+	if err != nil {
+		return nil, wrap(err)
+	}
 
-		if err != nil {
-			if os.IsNotExist(err) {
-				fmt.Printf("ERROR: path does not exist\n")
-				continue
-			} else {
-				return nil, wrap(err)
-			}
-		} else {
-			fileContents = fc
-			break
-		}
+	fileContents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, wrap(err)
 	}
 
 	return fileContents, nil
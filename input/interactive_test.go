@@ -0,0 +1,141 @@
+//go:build !windows
+
+package input
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lspaccatrosi16/go-cli-tools/termtest"
+)
+
+var fruit = []SelectOption{
+	{Name: "Apple", Value: "apple"},
+	{Name: "Banana", Value: "banana"},
+	{Name: "Cherry", Value: "cherry"},
+}
+
+func TestSelectionInteractive(t *testing.T) {
+	s := termtest.New(t, termtest.Options{})
+
+	var got string
+	var err error
+	s.Run(func() {
+		got, err = GetSelection("Pick a fruit", fruit)
+	})
+
+	s.Expect("Pick a fruit")
+	s.Expect("Cherry")
+	s.Press("down", "enter")
+	s.Wait()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "banana" {
+		t.Errorf("got %q, want banana", got)
+	}
+}
+
+func TestSearchableSelectionInteractive(t *testing.T) {
+	s := termtest.New(t, termtest.Options{})
+
+	var got string
+	var err error
+	s.Run(func() {
+		got, err = GetSearchableSelection("Pick a fruit", fruit)
+	})
+
+	s.Expect("type to search")
+	s.Type("chr")
+	s.ExpectGone("Banana")
+	s.Press("enter")
+	s.Wait()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "cherry" {
+		t.Errorf("got %q, want cherry", got)
+	}
+}
+
+func TestMultiSelectionInteractive(t *testing.T) {
+	s := termtest.New(t, termtest.Options{})
+
+	var got []string
+	var err error
+	s.Run(func() {
+		got, err = GetMultiSelection("Pick fruit", fruit, MultiSelectConfig{Min: 1})
+	})
+
+	s.Expect("space: toggle")
+	s.Press("enter")
+	s.Expect("select at least 1")
+	s.Press("space", "down", "down", "space", "enter")
+	s.Wait()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"apple", "cherry"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	s.Expect("Pick fruit: Apple, Cherry")
+}
+
+func TestSecretInputInteractive(t *testing.T) {
+	s := termtest.New(t, termtest.Options{})
+
+	var got string
+	var err error
+	s.Run(func() {
+		got, err = GetSecretInput("Password", SecretConfig{Mask: '*'})
+	})
+
+	s.Expect("Password?")
+	s.Type("hunter2")
+	s.Expect("*******")
+	s.Press("enter")
+	s.Wait()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "hunter2" {
+		t.Errorf("got %q, want hunter2", got)
+	}
+	if strings.Contains(s.Output(), "hunter2") {
+		t.Error("the secret was written to the terminal")
+	}
+	s.Expect("Password: " + secretPlaceholder)
+}
+
+func TestIntInteractive(t *testing.T) {
+	s := termtest.New(t, termtest.Options{})
+
+	max := 10
+	var got int
+	var err error
+	s.Run(func() {
+		got, err = GetInt("Count", RangeConfig[int]{Max: &max})
+	})
+
+	s.Expect("Count?")
+	s.Type("lots\r")
+	s.Expect(`"lots" is not a whole number`)
+	s.Type("11\r")
+	s.Expect("value must be at most 10")
+	s.Type("7\r")
+	s.Wait()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 7 {
+		t.Errorf("got %d, want 7", got)
+	}
+	s.Expect("Count: 7")
+	s.ExpectGone("ERROR")
+}
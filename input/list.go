@@ -0,0 +1,192 @@
+package input
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/lspaccatrosi16/go-cli-tools/internal/tty"
+)
+
+const listPageSize = 7
+
+type listState struct {
+	items     []SelectOption
+	visible   []int
+	matches   map[int][]int
+	cursor    int
+	scroll    int
+	query     string
+	searching bool
+	rendered  int
+	pageSize  int
+}
+
+func newListState(items []SelectOption, pageSize int) *listState {
+	if pageSize <= 0 {
+		pageSize = listPageSize
+	}
+
+	l := &listState{items: items, pageSize: pageSize}
+	l.filter()
+	return l
+}
+
+func (l *listState) filter() {
+	l.visible = []int{}
+	l.matches = map[int][]int{}
+	for _, result := range rankOptions(l.query, l.items) {
+		l.visible = append(l.visible, result.index)
+		l.matches[result.index] = result.positions
+	}
+	l.cursor = 0
+	l.scroll = 0
+}
+
+func (l *listState) name(idx int) string {
+	if !tty.Color() {
+		return l.items[idx].Name
+	}
+	return highlightMatches(l.items[idx].Name, l.matches[idx])
+}
+
+func (l *listState) itemLine(idx int, active bool, marker string) string {
+	t := GetTheme()
+	item := l.items[idx]
+
+	icon := t.padding()
+	if active {
+		icon = t.icon()
+	}
+
+	name := l.name(idx)
+	if marker != "" {
+		name = marker + " " + name
+	}
+
+	switch {
+	case item.Disabled:
+		if item.DisabledReason != "" {
+			name = fmt.Sprintf("%s (%s)", name, item.DisabledReason)
+		}
+		return fmt.Sprintf("%s %s", icon, t.style(t.DisabledColor, name))
+	case active:
+		return fmt.Sprintf("%s %s", icon, t.style(t.ActiveColor, name))
+	}
+
+	return fmt.Sprintf("%s %s", icon, t.style(t.InactiveColor, name))
+}
+
+func (l *listState) focus(idx int) {
+	for i, v := range l.visible {
+		if v == idx {
+			l.cursor = 0
+			l.scroll = 0
+			l.move(i)
+			return
+		}
+	}
+}
+
+func (l *listState) current() (int, bool) {
+	if len(l.visible) == 0 {
+		return -1, false
+	}
+	return l.visible[l.cursor], true
+}
+
+func (l *listState) move(delta int) {
+	if len(l.visible) == 0 {
+		return
+	}
+
+	l.cursor = (l.cursor + delta + len(l.visible)) % len(l.visible)
+
+	if l.cursor < l.scroll {
+		l.scroll = l.cursor
+	} else if l.cursor >= l.scroll+l.pageSize {
+		l.scroll = l.cursor - l.pageSize + 1
+	}
+}
+
+func (l *listState) handleSearchKey(key keyPress) bool {
+	switch key.code {
+	case keyRune:
+		l.query += string(key.r)
+	case keyBackspace:
+		if len(l.query) == 0 {
+			l.searching = false
+			return true
+		}
+		q := []rune(l.query)
+		l.query = string(q[:len(q)-1])
+	case keyEscape:
+		l.query = ""
+		l.searching = false
+	case keyEnter:
+		l.searching = false
+		return true
+	default:
+		return false
+	}
+
+	l.filter()
+	return true
+}
+
+func (l *listState) draw(header string, line func(idx int, active bool) string, footer string) {
+	buf := bytes.NewBuffer(nil)
+
+	l.clear(buf)
+
+	lines := []string{header}
+
+	if l.searching || l.query != "" {
+		lines = append(lines, fmt.Sprintf("  Search: %s", l.query))
+	}
+
+	end := l.scroll + l.pageSize
+	if end > len(l.visible) {
+		end = len(l.visible)
+	}
+
+	for i := l.scroll; i < end; i++ {
+		lines = append(lines, line(l.visible[i], i == l.cursor))
+	}
+
+	if len(l.visible) == 0 {
+		lines = append(lines, "  no matches")
+	}
+
+	if idx, ok := l.current(); ok && l.items[idx].Description != "" {
+		t := GetTheme()
+		lines = append(lines, t.style(t.DescriptionColor, l.items[idx].Description))
+	}
+
+	if footer != "" {
+		lines = append(lines, footer)
+	}
+
+	fmt.Fprint(buf, strings.Join(lines, "\r\n"))
+	l.rendered = len(lines)
+
+	fmt.Print(buf.String())
+}
+
+func (l *listState) clear(buf *bytes.Buffer) {
+	if l.rendered == 0 {
+		return
+	}
+
+	fmt.Fprint(buf, "\r", LINE_CLEAR)
+	for i := 1; i < l.rendered; i++ {
+		fmt.Fprint(buf, LINE_UP, LINE_CLEAR)
+	}
+	l.rendered = 0
+}
+
+func (l *listState) erase() {
+	buf := bytes.NewBuffer(nil)
+	l.clear(buf)
+	fmt.Print(buf.String())
+}
@@ -0,0 +1,165 @@
+package input
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lspaccatrosi16/go-cli-tools/internal/tty"
+)
+
+type MultiSelectConfig struct {
+	Min      int
+	Max      int
+	Selected []string
+	PageSize int
+}
+
+func GetMultiSelection(label string, items []SelectOption, config MultiSelectConfig) ([]string, error) {
+	if config.Max > 0 && config.Min > config.Max {
+		return nil, wrap(fmt.Errorf("minimum selection %d is greater than maximum %d", config.Min, config.Max))
+	}
+
+	chosen := make([]bool, len(items))
+	for i, item := range items {
+		for _, v := range config.Selected {
+			if item.Value == v && !item.Disabled {
+				chosen[i] = true
+			}
+		}
+	}
+
+	if !tty.Interactive() {
+		err := plainMultiSelect(label, items, chosen, config)
+		if err != nil {
+			return nil, err
+		}
+
+		values, _ := collectChosen(items, chosen)
+		return values, nil
+	}
+
+	restore, err := makeRaw()
+	if err != nil {
+		return nil, err
+	}
+	defer restore()
+
+	state := newListState(items, config.PageSize)
+	header := fmt.Sprintf("? %s (space: toggle, a: all, n: none, /: search, enter: done)", label)
+	message := ""
+
+	line := func(idx int, active bool) string {
+		box := "[ ]"
+		if chosen[idx] {
+			box = "[x]"
+		}
+		return state.itemLine(idx, active, box)
+	}
+
+	count := func() int {
+		n := 0
+		for _, c := range chosen {
+			if c {
+				n++
+			}
+		}
+		return n
+	}
+
+	for {
+		footer := ""
+		if message != "" {
+			footer = fmt.Sprintf("ERROR: %s", message)
+		}
+		state.draw(header, line, footer)
+		message = ""
+
+		key, err := readKey(tty.Stdin())
+		if err != nil {
+			state.erase()
+			return nil, err
+		}
+
+		if key.code == keyInterrupt || key.code == keyEOF {
+			state.erase()
+			return nil, wrap(ErrInterrupt)
+		}
+
+		if state.searching && state.handleSearchKey(key) {
+			continue
+		}
+
+		switch key.code {
+		case keyUp:
+			state.move(-1)
+		case keyDown:
+			state.move(1)
+		case keyEnter:
+			if n := count(); n < config.Min {
+				message = fmt.Sprintf("select at least %d", config.Min)
+				continue
+			}
+
+			state.erase()
+
+			values, names := collectChosen(items, chosen)
+			fmt.Printf("%s: %s\r\n", label, strings.Join(names, ", "))
+			return values, nil
+		case keyRune:
+			switch key.r {
+			case ' ':
+				idx, ok := state.current()
+				if !ok {
+					continue
+				}
+				if items[idx].Disabled {
+					message = disabledError(items[idx]).Error()
+					continue
+				}
+				if !chosen[idx] && config.Max > 0 && count() >= config.Max {
+					message = fmt.Sprintf("select at most %d", config.Max)
+					continue
+				}
+				chosen[idx] = !chosen[idx]
+			case 'a':
+				for _, idx := range state.visible {
+					if items[idx].Disabled {
+						continue
+					}
+					if !chosen[idx] && config.Max > 0 && count() >= config.Max {
+						message = fmt.Sprintf("select at most %d", config.Max)
+						break
+					}
+					chosen[idx] = true
+				}
+			case 'n':
+				for _, idx := range state.visible {
+					chosen[idx] = false
+				}
+			case '/':
+				state.searching = true
+			case 'k':
+				state.move(-1)
+			case 'j':
+				state.move(1)
+			}
+		case keyEscape:
+			if state.query != "" {
+				state.query = ""
+				state.filter()
+			}
+		}
+	}
+}
+
+func collectChosen(items []SelectOption, chosen []bool) ([]string, []string) {
+	values := []string{}
+	names := []string{}
+	for i, item := range items {
+		if chosen[i] {
+			values = append(values, item.Value)
+			names = append(names, item.Name)
+		}
+	}
+	return values, names
+}
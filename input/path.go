@@ -0,0 +1,221 @@
+package input
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chzyer/readline"
+	"github.com/lspaccatrosi16/go-cli-tools/internal/tty"
+)
+
+type PathKind int
+
+const (
+	PathAny PathKind = iota
+	PathFile
+	PathDir
+)
+
+type PathConfig struct {
+	Kind         PathKind
+	Extensions   []string
+	MustExist    bool
+	MustNotExist bool
+}
+
+func GetPathInput(label string, config PathConfig) (string, error) {
+	if config.MustExist && config.MustNotExist {
+		return "", wrap(fmt.Errorf("path cannot be required to both exist and not exist"))
+	}
+
+	validator := func(str string) error {
+		return validatePath(str, config)
+	}
+
+	if !tty.Interactive() {
+		path, err := GetValidatedInputErr(label, validator)
+		if err != nil {
+			return "", err
+		}
+		return expandPath(path)
+	}
+
+	rl, err := readline.NewEx(&readline.Config{
+		Prompt:       fmt.Sprintf("%s? ", label),
+		AutoComplete: &pathCompleter{config: config},
+	})
+	if err != nil {
+		return "", wrap(err)
+	}
+	defer rl.Close()
+
+	for {
+		line, err := rl.Readline()
+		if err == readline.ErrInterrupt {
+			return "", wrap(ErrInterrupt)
+		} else if err != nil {
+			return "", wrap(err)
+		}
+
+		line = strings.TrimSpace(line)
+
+		validationError := validator(line)
+		if validationError != nil {
+			fmt.Printf("ERROR: %s\n", validationError.Error())
+			continue
+		}
+
+		return expandPath(line)
+	}
+}
+
+func GetPathReader(label string, config PathConfig) (io.ReadCloser, error) {
+	config.Kind = PathFile
+	config.MustExist = true
+	config.MustNotExist = false
+
+	path, err := GetPathInput(label, config)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, wrap(err)
+	}
+
+	return f, nil
+}
+
+func expandPath(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		return filepath.Clean(path), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", wrap(err)
+	}
+
+	return filepath.Join(home, path[1:]), nil
+}
+
+func hasExtension(path string, extensions []string) bool {
+	if len(extensions) == 0 {
+		return true
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range extensions {
+		e = strings.ToLower(e)
+		if !strings.HasPrefix(e, ".") {
+			e = "." + e
+		}
+		if ext == e {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validatePath(str string, config PathConfig) error {
+	if str == "" {
+		return fmt.Errorf("path must not be empty")
+	}
+
+	path, err := expandPath(str)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if info == nil {
+		if config.MustExist {
+			return fmt.Errorf("path does not exist")
+		}
+
+		parent, err := os.Stat(filepath.Dir(path))
+		if err != nil || !parent.IsDir() {
+			return fmt.Errorf("parent directory %s does not exist", filepath.Dir(path))
+		}
+	} else {
+		if config.MustNotExist {
+			return fmt.Errorf("path already exists")
+		}
+		if config.Kind == PathFile && info.IsDir() {
+			return fmt.Errorf("path is a directory")
+		}
+		if config.Kind == PathDir && !info.IsDir() {
+			return fmt.Errorf("path is not a directory")
+		}
+	}
+
+	if config.Kind != PathDir && !hasExtension(path, config.Extensions) {
+		return fmt.Errorf("path must have one of the extensions %s", strings.Join(config.Extensions, ", "))
+	}
+
+	return nil
+}
+
+type pathCompleter struct {
+	config PathConfig
+}
+
+func (p *pathCompleter) Do(line []rune, pos int) ([][]rune, int) {
+	typed := string(line[:pos])
+
+	dir, prefix := filepath.Split(typed)
+	listDir := dir
+	if listDir == "" {
+		listDir = "."
+	}
+
+	expanded, err := expandPath(listDir)
+	if err != nil {
+		return nil, 0
+	}
+
+	entries, err := os.ReadDir(expanded)
+	if err != nil {
+		return nil, 0
+	}
+
+	candidates := [][]rune{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if strings.HasPrefix(name, ".") && !strings.HasPrefix(prefix, ".") {
+			continue
+		}
+
+		isDir := entry.IsDir()
+		if !isDir {
+			if info, err := os.Stat(filepath.Join(expanded, name)); err == nil {
+				isDir = info.IsDir()
+			}
+		}
+
+		if isDir {
+			candidates = append(candidates, []rune(name[len(prefix):]+string(filepath.Separator)))
+			continue
+		}
+
+		if p.config.Kind == PathDir || !hasExtension(name, p.config.Extensions) {
+			continue
+		}
+
+		candidates = append(candidates, []rune(name[len(prefix):]))
+	}
+
+	return candidates, len([]rune(prefix))
+}
@@ -0,0 +1,53 @@
+package input
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetPathInputPiped(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "a.txt")
+	if err := os.WriteFile(existing, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		input  string
+		config PathConfig
+		want   string
+		eof    bool
+	}{
+		{"existing", existing + "\n", PathConfig{MustExist: true}, existing, false},
+		{"retries", dir + "/b.txt\n" + existing + "\n", PathConfig{MustExist: true}, existing, false},
+		{"new", dir + "/b.txt\n", PathConfig{MustNotExist: true}, filepath.Join(dir, "b.txt"), false},
+		{"cleaned", dir + "/x/../a.txt\n", PathConfig{Kind: PathFile}, existing, false},
+		{"extension", existing + "\n", PathConfig{Extensions: []string{"md"}}, "", true},
+		{"nothing", "", PathConfig{MustNotExist: true}, "", true},
+		{"invalid at eof", existing, PathConfig{MustNotExist: true}, "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			withStdin(t, test.input)
+
+			got, err := GetPathInput("Path", test.config)
+			if test.eof {
+				if !errors.Is(err, io.EOF) {
+					t.Fatalf("got %q, %v, want io.EOF", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Fatalf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
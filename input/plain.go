@@ -0,0 +1,181 @@
+package input
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/lspaccatrosi16/go-cli-tools/internal/tty"
+)
+
+// SetAccessibleMode disables redraws and cursor movement in every prompt so
+// that screen readers can follow the output. It can also be enabled by setting
+// the ACCESSIBLE environment variable.
+func SetAccessibleMode(on bool) {
+	tty.SetAccessible(on)
+}
+
+func readPlainLine() (string, error) {
+	line, err := tty.Stdin().ReadString('\n')
+	line = strings.TrimSpace(line)
+
+	// piped input is not echoed, so echo it to keep logs readable
+	if !tty.StdinIsTerminal() {
+		fmt.Println(line)
+	}
+
+	if err != nil && line == "" {
+		if err == io.EOF {
+			return "", wrap(io.EOF)
+		}
+		return "", wrap(err)
+	}
+
+	return line, nil
+}
+
+func plainOptionName(item SelectOption) string {
+	name := item.Name
+	if item.Description != "" {
+		name = fmt.Sprintf("%s - %s", name, item.Description)
+	}
+	if item.Disabled {
+		reason := "unavailable"
+		if item.DisabledReason != "" {
+			reason = item.DisabledReason
+		}
+		name = fmt.Sprintf("%s (%s)", name, reason)
+	}
+	return name
+}
+
+func plainSelect(label string, items []SelectOption, config SelectConfig, searchable bool) (int, error) {
+	visible := []int{}
+	for i := range items {
+		visible = append(visible, i)
+	}
+
+	def := -1
+	if config.Default != "" {
+		def = defaultIndex(items, config.Default)
+	}
+
+	prompt := "Enter a number"
+	if searchable {
+		prompt = "Enter a number or text to search"
+	}
+	if def != -1 {
+		prompt = fmt.Sprintf("%s [%s]", prompt, items[def].Name)
+	}
+
+	for {
+		fmt.Printf("%s:\n", label)
+		for n, idx := range visible {
+			fmt.Printf("  %d) %s\n", n+1, plainOptionName(items[idx]))
+		}
+		fmt.Printf("%s? ", prompt)
+
+		line, err := readPlainLine()
+		if err != nil {
+			return -1, err
+		}
+
+		if line == "" && def != -1 {
+			return def, nil
+		}
+
+		n, convErr := strconv.Atoi(line)
+		if convErr == nil && n >= 1 && n <= len(visible) {
+			if item := items[visible[n-1]]; item.Disabled {
+				fmt.Printf("ERROR: %s\n", disabledError(item).Error())
+				continue
+			}
+			return visible[n-1], nil
+		}
+
+		if searchable && convErr != nil {
+			results := rankOptions(line, items)
+			if len(results) == 0 {
+				fmt.Printf("ERROR: nothing matches %q\n", line)
+				continue
+			}
+
+			visible = []int{}
+			for _, r := range results {
+				visible = append(visible, r.index)
+			}
+			continue
+		}
+
+		fmt.Printf("ERROR: enter a number between 1 and %d\n", len(visible))
+	}
+}
+
+func plainMultiSelect(label string, items []SelectOption, chosen []bool, config MultiSelectConfig) error {
+	for {
+		fmt.Printf("%s:\n", label)
+		for i, item := range items {
+			box := "[ ]"
+			if chosen[i] {
+				box = "[x]"
+			}
+			fmt.Printf("  %d) %s %s\n", i+1, box, plainOptionName(item))
+		}
+		fmt.Print("Enter numbers separated by commas, all or none (empty keeps the marked items)? ")
+
+		line, err := readPlainLine()
+		if err != nil {
+			return err
+		}
+
+		next := make([]bool, len(chosen))
+		switch strings.ToLower(line) {
+		case "":
+			copy(next, chosen)
+		case "all":
+			for i := range next {
+				next[i] = !items[i].Disabled
+			}
+		case "none":
+		default:
+			valid := true
+			for _, part := range strings.Split(line, ",") {
+				n, err := strconv.Atoi(strings.TrimSpace(part))
+				if err != nil || n < 1 || n > len(items) {
+					fmt.Printf("ERROR: %q is not a number between 1 and %d\n", strings.TrimSpace(part), len(items))
+					valid = false
+					break
+				}
+				if items[n-1].Disabled {
+					fmt.Printf("ERROR: %s\n", disabledError(items[n-1]).Error())
+					valid = false
+					break
+				}
+				next[n-1] = true
+			}
+			if !valid {
+				continue
+			}
+		}
+
+		count := 0
+		for _, c := range next {
+			if c {
+				count++
+			}
+		}
+
+		if count < config.Min {
+			fmt.Printf("ERROR: select at least %d\n", config.Min)
+			continue
+		}
+		if config.Max > 0 && count > config.Max {
+			fmt.Printf("ERROR: select at most %d\n", config.Max)
+			continue
+		}
+
+		copy(chosen, next)
+		return nil
+	}
+}
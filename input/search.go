@@ -0,0 +1,71 @@
+package input
+
+import (
+	"fmt"
+
+	"github.com/lspaccatrosi16/go-cli-tools/internal/tty"
+)
+
+func runSearchableSelect(label string, items []SelectOption, config SelectConfig) (int, error) {
+	if !tty.Interactive() {
+		return plainSelect(label, items, config, true)
+	}
+
+	restore, err := makeRaw()
+	if err != nil {
+		return -1, err
+	}
+	defer restore()
+
+	state := newListState(items, config.PageSize)
+	state.searching = true
+	state.focus(defaultIndex(items, config.Default))
+	header := fmt.Sprintf("? %s (type to search, esc: clear)", label)
+	message := ""
+
+	line := func(idx int, active bool) string {
+		return state.itemLine(idx, active, "")
+	}
+
+	for {
+		footer := ""
+		if message != "" {
+			footer = fmt.Sprintf("ERROR: %s", message)
+		}
+		state.draw(header, line, footer)
+		message = ""
+
+		key, err := readKey(tty.Stdin())
+		if err != nil {
+			state.erase()
+			return -1, err
+		}
+
+		switch key.code {
+		case keyInterrupt, keyEOF:
+			state.erase()
+			return -1, wrap(ErrInterrupt)
+		case keyEnter:
+			idx, ok := state.current()
+			if !ok {
+				continue
+			}
+			if items[idx].Disabled {
+				message = disabledError(items[idx]).Error()
+				continue
+			}
+			state.erase()
+			return idx, nil
+		case keyUp:
+			state.move(-1)
+		case keyDown:
+			state.move(1)
+		case keyEscape:
+			state.query = ""
+			state.filter()
+		default:
+			state.handleSearchKey(key)
+			state.searching = true
+		}
+	}
+}
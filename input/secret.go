@@ -0,0 +1,124 @@
+package input
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lspaccatrosi16/go-cli-tools/internal/tty"
+)
+
+const secretPlaceholder = "********"
+
+type SecretConfig struct {
+	Mask      rune
+	Confirm   bool
+	Validator func(str string) error
+}
+
+func GetSecretInput(label string, config SecretConfig) (string, error) {
+	var result string
+	linesUsed := 0
+
+	for {
+		value, err := readSecret(label, config.Mask)
+		linesUsed++
+		if err != nil {
+			return "", err
+		}
+
+		if config.Validator != nil {
+			if validationError := config.Validator(value); validationError != nil {
+				fmt.Printf("ERROR: %s\n", validationError.Error())
+				linesUsed++
+				continue
+			}
+		}
+
+		if config.Confirm {
+			confirm, err := readSecret("Confirm "+strings.ToLower(label), config.Mask)
+			linesUsed++
+			if err != nil {
+				return "", err
+			}
+
+			if confirm != value {
+				fmt.Println("ERROR: values do not match")
+				linesUsed++
+				continue
+			}
+		}
+
+		result = value
+		break
+	}
+
+	if !tty.Interactive() {
+		return result, nil
+	}
+
+	for i := 0; i < linesUsed; i++ {
+		fmt.Print(LINE_UP)
+		fmt.Print(LINE_CLEAR)
+	}
+
+	fmt.Printf("%s: %s\n", label, secretPlaceholder)
+
+	return result, nil
+}
+
+func readSecret(label string, mask rune) (string, error) {
+	fmt.Printf("%s? ", label)
+
+	if !tty.StdinIsTerminal() {
+		line, err := tty.Stdin().ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return "", wrap(err)
+		}
+		fmt.Println()
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	restore, err := makeRaw()
+	if err != nil {
+		return "", err
+	}
+	defer restore()
+
+	value := []rune{}
+
+	for {
+		key, err := readKey(tty.Stdin())
+		if err != nil {
+			fmt.Print("\r\n")
+			return "", err
+		}
+
+		switch key.code {
+		case keyRune:
+			value = append(value, key.r)
+			if mask != 0 {
+				fmt.Print(string(mask))
+			}
+		case keyBackspace:
+			if len(value) > 0 {
+				value = value[:len(value)-1]
+				if mask != 0 {
+					fmt.Print("\b \b")
+				}
+			}
+		case keyEnter:
+			fmt.Print("\r\n")
+			return string(value), nil
+		case keyInterrupt:
+			fmt.Print("\r\n")
+			return "", wrap(ErrInterrupt)
+		case keyEOF:
+			// ctrl-d only ends an empty answer, as in a shell
+			if len(value) == 0 {
+				fmt.Print("\r\n")
+				return "", wrap(io.EOF)
+			}
+		}
+	}
+}
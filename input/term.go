@@ -0,0 +1,138 @@
+package input
+
+import (
+	"bufio"
+	"os"
+	"sync"
+
+	"github.com/manifoldco/promptui"
+	"golang.org/x/term"
+)
+
+var ErrInterrupt = promptui.ErrInterrupt
+
+type keyCode int
+
+const (
+	keyRune keyCode = iota
+	keyEnter
+	keyBackspace
+	keyTab
+	keyEscape
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyInterrupt
+	keyEOF
+)
+
+type keyPress struct {
+	code keyCode
+	r    rune
+}
+
+// promptOutput passes the output of a promptui prompt to stdout until it is
+// closed. readline goes on redrawing for a moment after a prompt returns,
+// which would otherwise erase whatever is printed next.
+type promptOutput struct {
+	mu     sync.Mutex
+	closed bool
+}
+
+func (p *promptOutput) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return len(b), nil
+	}
+	return os.Stdout.Write(b)
+}
+
+func (p *promptOutput) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	return nil
+}
+
+func makeRaw() (func(), error) {
+	fd := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, wrap(err)
+	}
+
+	return func() {
+		term.Restore(fd, state)
+	}, nil
+}
+
+func readKey(r *bufio.Reader) (keyPress, error) {
+	// a closed reader is an error, unlike ctrl-d, since reading again would
+	// only return io.EOF forever
+	c, _, err := r.ReadRune()
+	if err != nil {
+		return keyPress{}, wrap(err)
+	}
+
+	switch c {
+	case '\r', '\n':
+		return keyPress{code: keyEnter}, nil
+	case 127, '\b':
+		return keyPress{code: keyBackspace}, nil
+	case '\t':
+		return keyPress{code: keyTab}, nil
+	case 3:
+		return keyPress{code: keyInterrupt}, nil
+	case 4:
+		return keyPress{code: keyEOF}, nil
+	case 27:
+		return readEscape(r)
+	}
+
+	return keyPress{code: keyRune, r: c}, nil
+}
+
+func readEscape(r *bufio.Reader) (keyPress, error) {
+	if r.Buffered() == 0 {
+		return keyPress{code: keyEscape}, nil
+	}
+
+	next, err := r.ReadByte()
+	if err != nil {
+		return keyPress{}, wrap(err)
+	}
+
+	if next != '[' && next != 'O' {
+		return keyPress{code: keyEscape}, nil
+	}
+
+	final, err := r.ReadByte()
+	if err != nil {
+		return keyPress{}, wrap(err)
+	}
+
+	// skip parameters such as the "1;5" in "\x1b[1;5A"
+	for (final >= '0' && final <= '9') || final == ';' {
+		final, err = r.ReadByte()
+		if err != nil {
+			return keyPress{}, wrap(err)
+		}
+	}
+
+	switch final {
+	case 'A':
+		return keyPress{code: keyUp}, nil
+	case 'B':
+		return keyPress{code: keyDown}, nil
+	case 'C':
+		return keyPress{code: keyRight}, nil
+	case 'D':
+		return keyPress{code: keyLeft}, nil
+	}
+
+	return keyPress{code: keyEscape}, nil
+}
@@ -0,0 +1,43 @@
+package input
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestReadKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []keyPress
+	}{
+		{"runes", "aé", []keyPress{{code: keyRune, r: 'a'}, {code: keyRune, r: 'é'}}},
+		{"enter", "\r\n", []keyPress{{code: keyEnter}, {code: keyEnter}}},
+		{"control", "\x7f\t\x03\x04", []keyPress{{code: keyBackspace}, {code: keyTab}, {code: keyInterrupt}, {code: keyEOF}}},
+		{"arrows", "\x1b[A\x1b[B\x1bOC\x1b[1;5D", []keyPress{{code: keyUp}, {code: keyDown}, {code: keyRight}, {code: keyLeft}}},
+		{"escape", "\x1b", []keyPress{{code: keyEscape}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := bufio.NewReader(strings.NewReader(test.input))
+			for _, want := range test.want {
+				got, err := readKey(r)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != want {
+					t.Fatalf("got %+v, want %+v", got, want)
+				}
+			}
+
+			// a closed reader must end the prompt rather than look like ctrl-d
+			if _, err := readKey(r); !errors.Is(err, io.EOF) {
+				t.Fatalf("got %v at the end of input, want io.EOF", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package input
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/lspaccatrosi16/go-cli-tools/internal/tty"
+	"github.com/manifoldco/promptui"
+)
+
+// Theme controls how every selector renders. Colours are the names of the
+// promptui template functions, e.g. "green", "cyan", "faint" or "bold".
+type Theme struct {
+	ActiveIcon       string
+	ActiveColor      string
+	InactiveColor    string
+	DisabledColor    string
+	DescriptionColor string
+	LabelColor       string
+	Templates        *promptui.SelectTemplates
+}
+
+var themeLock = &sync.Mutex{}
+
+var theme = DefaultTheme()
+
+func DefaultTheme() Theme {
+	return Theme{
+		ActiveIcon:       "▸",
+		ActiveColor:      "green",
+		DisabledColor:    "faint",
+		DescriptionColor: "faint",
+	}
+}
+
+func SetTheme(t Theme) {
+	themeLock.Lock()
+	defer themeLock.Unlock()
+	theme = t
+}
+
+func GetTheme() Theme {
+	themeLock.Lock()
+	defer themeLock.Unlock()
+	return theme
+}
+
+func (t Theme) style(color string, str string) string {
+	if color == "" || !tty.Color() {
+		return str
+	}
+
+	styler, ok := promptui.FuncMap[color].(func(interface{}) string)
+	if !ok {
+		return str
+	}
+	return styler(str)
+}
+
+func (t Theme) icon() string {
+	if t.ActiveIcon == "" {
+		return "▸"
+	}
+	return t.ActiveIcon
+}
+
+func (t Theme) padding() string {
+	return fmt.Sprintf("%*s", len([]rune(t.icon())), "")
+}
+
+func (t Theme) pipe(color string) string {
+	if color == "" || !tty.Color() {
+		return ""
+	}
+	return " | " + color
+}
+
+func (t Theme) selectTemplates() *promptui.SelectTemplates {
+	if t.Templates != nil {
+		return t.Templates
+	}
+
+	disabled := fmt.Sprintf(`{{ if .DisabledReason }}{{ print .Name " (" .DisabledReason ")"%[1]s }}{{ else }}{{ .Name%[1]s }}{{ end }}`, t.pipe(t.DisabledColor))
+
+	templates := &promptui.SelectTemplates{
+		Active:   fmt.Sprintf(`{{ if .Disabled }}%s %s{{ else }}%s {{ .Name%s }}{{ end }}`, t.icon(), disabled, t.icon(), t.pipe(t.ActiveColor)),
+		Inactive: fmt.Sprintf(`{{ if .Disabled }}%s %s{{ else }}%s {{ .Name%s }}{{ end }}`, t.padding(), disabled, t.padding(), t.pipe(t.InactiveColor)),
+		Selected: "{{ .Name }}",
+		Details:  fmt.Sprintf(`{{ if .Description }}{{ .Description%s }}{{ end }}`, t.pipe(t.DescriptionColor)),
+	}
+
+	if t.LabelColor != "" && tty.Color() {
+		templates.Label = fmt.Sprintf(`{{ "?"%[1]s }} {{ .%[1]s }}:`, t.pipe(t.LabelColor))
+	}
+
+	if !tty.Color() {
+		templates.Label = "? {{ . }}:"
+		templates.Help = "Use the arrow keys to navigate"
+	}
+
+	return templates
+}
@@ -0,0 +1,191 @@
+package input
+
+import (
+	"cmp"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type RangeConfig[T any] struct {
+	Default *T
+	Min     *T
+	Max     *T
+}
+
+type TextConfig struct {
+	Default string
+	Pattern *regexp.Regexp
+}
+
+// GetParsedInput asks until parse accepts the answer. An empty answer keeps def
+// when it is not nil. It returns io.EOF when stdin ends first.
+func GetParsedInput[T any](label string, def *T, format func(T) string, parse func(string) (T, error)) (T, error) {
+	var result T
+
+	prompt := label
+	if def != nil {
+		prompt = fmt.Sprintf("%s [%s]", label, format(*def))
+	}
+
+	validator := func(str string) error {
+		str = strings.TrimSpace(str)
+		if str == "" && def != nil {
+			result = *def
+			return nil
+		}
+
+		v, err := parse(str)
+		if err != nil {
+			return err
+		}
+
+		result = v
+		return nil
+	}
+
+	display := func(string) string {
+		return format(result)
+	}
+
+	if _, err := getValidatedInput(prompt, validator, display); err != nil {
+		return *new(T), err
+	}
+
+	return result, nil
+}
+
+func checkOrdered[T cmp.Ordered](v T, config RangeConfig[T], format func(T) string) error {
+	if config.Min != nil && v < *config.Min {
+		return fmt.Errorf("value must be at least %s", format(*config.Min))
+	}
+	if config.Max != nil && v > *config.Max {
+		return fmt.Errorf("value must be at most %s", format(*config.Max))
+	}
+	return nil
+}
+
+func checkPattern(str string, config TextConfig) error {
+	if config.Pattern != nil && !config.Pattern.MatchString(str) {
+		return fmt.Errorf("value must match %s", config.Pattern.String())
+	}
+	return nil
+}
+
+func textDefault(config TextConfig) *string {
+	if config.Default == "" {
+		return nil
+	}
+	return &config.Default
+}
+
+func GetInt(label string, config RangeConfig[int]) (int, error) {
+	format := strconv.Itoa
+
+	parse := func(str string) (int, error) {
+		v, err := strconv.Atoi(str)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a whole number", str)
+		}
+		return v, checkOrdered(v, config, format)
+	}
+
+	return GetParsedInput(label, config.Default, format, parse)
+}
+
+func GetFloat(label string, config RangeConfig[float64]) (float64, error) {
+	format := func(f float64) string {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+
+	parse := func(str string) (float64, error) {
+		v, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a number", str)
+		}
+		return v, checkOrdered(v, config, format)
+	}
+
+	return GetParsedInput(label, config.Default, format, parse)
+}
+
+func GetDuration(label string, config RangeConfig[time.Duration]) (time.Duration, error) {
+	format := time.Duration.String
+
+	parse := func(str string) (time.Duration, error) {
+		v, err := time.ParseDuration(str)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a duration (e.g. 1h30m, 45s)", str)
+		}
+		return v, checkOrdered(v, config, format)
+	}
+
+	return GetParsedInput(label, config.Default, format, parse)
+}
+
+func GetDate(label string, layout string, config RangeConfig[time.Time]) (time.Time, error) {
+	if layout == "" {
+		layout = time.DateOnly
+	}
+
+	format := func(t time.Time) string {
+		return t.Format(layout)
+	}
+
+	parse := func(str string) (time.Time, error) {
+		v, err := time.Parse(layout, str)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%q does not match the format %s", str, layout)
+		}
+		if config.Min != nil && v.Before(*config.Min) {
+			return v, fmt.Errorf("date must not be before %s", format(*config.Min))
+		}
+		if config.Max != nil && v.After(*config.Max) {
+			return v, fmt.Errorf("date must not be after %s", format(*config.Max))
+		}
+		return v, nil
+	}
+
+	return GetParsedInput(label, config.Default, format, parse)
+}
+
+func GetURL(label string, config TextConfig) (*url.URL, error) {
+	format := func(u *url.URL) string {
+		return u.String()
+	}
+
+	parse := func(str string) (*url.URL, error) {
+		u, err := url.ParseRequestURI(str)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("%q is not an absolute url", str)
+		}
+		return u, checkPattern(str, config)
+	}
+
+	var def **url.URL
+	if u, err := url.Parse(config.Default); config.Default != "" && err == nil {
+		def = &u
+	}
+
+	return GetParsedInput(label, def, format, parse)
+}
+
+func GetEmail(label string, config TextConfig) (string, error) {
+	format := func(s string) string {
+		return s
+	}
+
+	parse := func(str string) (string, error) {
+		addr, err := mail.ParseAddress(str)
+		if err != nil || addr.Address != str {
+			return "", fmt.Errorf("%q is not an email address", str)
+		}
+		return str, checkPattern(str, config)
+	}
+
+	return GetParsedInput(label, textDefault(config), format, parse)
+}
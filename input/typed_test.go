@@ -0,0 +1,93 @@
+package input
+
+import (
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/lspaccatrosi16/go-cli-tools/internal/tty"
+)
+
+// withStdin makes prompts read text, as when input is piped in.
+func withStdin(t *testing.T, text string) {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.WriteString(text); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	old := os.Stdin
+	os.Stdin = r
+	tty.ResetStdin()
+
+	t.Cleanup(func() {
+		os.Stdin = old
+		r.Close()
+		tty.ResetStdin()
+	})
+}
+
+func TestGetInt(t *testing.T) {
+	min, max, def := 1, 10, 5
+
+	tests := []struct {
+		name  string
+		input string
+		want  int
+		eof   bool
+	}{
+		{"valid", "7\n", 7, false},
+		{"retries", "abc\n11\n3\n", 3, false},
+		{"default", "\n", 5, false},
+		{"no newline", "8", 8, false},
+		{"invalid at eof", "abc", 0, true},
+		{"out of range at eof", "abc\n20", 0, true},
+		{"nothing", "", 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			withStdin(t, test.input)
+
+			got, err := GetInt("Number", RangeConfig[int]{Min: &min, Max: &max, Default: &def})
+			if test.eof {
+				if !errors.Is(err, io.EOF) {
+					t.Fatalf("got %d, %v, want io.EOF", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Fatalf("got %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGetValidatedInputErr(t *testing.T) {
+	notEmpty := func(str string) error {
+		if str == "" {
+			return errors.New("must not be empty")
+		}
+		return nil
+	}
+
+	withStdin(t, "\n")
+	if _, err := GetValidatedInputErr("Name", notEmpty); !errors.Is(err, io.EOF) {
+		t.Fatalf("got %v, want io.EOF", err)
+	}
+
+	withStdin(t, "\nbob\n")
+	got, err := GetValidatedInputErr("Name", notEmpty)
+	if err != nil || got != "bob" {
+		t.Fatalf("got %q, %v", got, err)
+	}
+}
@@ -1,6 +1,14 @@
 package input
 
 type SelectOption struct {
-	Name  string
-	Value string
+	Name           string
+	Value          string
+	Description    string
+	Disabled       bool
+	DisabledReason string
+}
+
+type SelectConfig struct {
+	Default  string
+	PageSize int
 }
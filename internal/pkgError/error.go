@@ -8,12 +8,19 @@ import (
 type packageError struct {
 	err string
 	pkg string
+	// cause is the wrapped error, so that errors.Is still finds io.EOF and
+	// the like
+	cause error
 }
 
 func (e *packageError) Error() string {
 	return fmt.Sprintf("go-cli-tools/%s: %s", e.pkg, e.err)
 }
 
+func (e *packageError) Unwrap() error {
+	return e.cause
+}
+
 func WrapError(pkg string, err error) error {
 	if err == nil {
 		return nil
@@ -28,7 +35,7 @@ func WrapError(pkg string, err error) error {
 		return err
 	}
 
-	return Error(pkg, str)
+	return &packageError{str, pkg, err}
 }
 
 func WrapErrorFactory(pkg string) func(error) error {
@@ -38,12 +45,12 @@ func WrapErrorFactory(pkg string) func(error) error {
 }
 
 func Error(pkg, err string) error {
-	return &packageError{err, pkg}
+	return &packageError{err: err, pkg: pkg}
 }
 
 func Errorf(pkg, err string, a ...any) error {
 	formatted := fmt.Sprintf(err, a...)
-	return &packageError{formatted, pkg}
+	return &packageError{err: formatted, pkg: pkg}
 }
 
 func ErrorFactory(pkg string) func(string) error {
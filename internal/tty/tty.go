@@ -0,0 +1,61 @@
+package tty
+
+import (
+	"bufio"
+	"os"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+var mu sync.Mutex
+var accessible = os.Getenv("ACCESSIBLE") != ""
+var stdin = bufio.NewReader(os.Stdin)
+
+func SetAccessible(on bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	accessible = on
+}
+
+// Accessible reports whether output should avoid cursor movement and redraws
+// so that screen readers can follow it.
+func Accessible() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return accessible
+}
+
+func StdinIsTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+func StdoutIsTerminal() bool {
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// Interactive reports whether prompts may take over the terminal.
+func Interactive() bool {
+	return StdinIsTerminal() && StdoutIsTerminal() && !Accessible()
+}
+
+// Color reports whether ANSI colours may be written, honouring NO_COLOR.
+func Color() bool {
+	return os.Getenv("NO_COLOR") == "" && StdoutIsTerminal()
+}
+
+// Stdin returns the reader shared by every prompt, so that input read ahead by
+// one prompt is still seen by the next.
+func Stdin() *bufio.Reader {
+	mu.Lock()
+	defer mu.Unlock()
+	return stdin
+}
+
+// ResetStdin discards anything read ahead from stdin. It must be called when
+// the file behind os.Stdin changes.
+func ResetStdin() {
+	mu.Lock()
+	defer mu.Unlock()
+	stdin = bufio.NewReader(os.Stdin)
+}
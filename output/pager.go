@@ -0,0 +1,135 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/lspaccatrosi16/go-cli-tools/internal/pkgError"
+	"github.com/lspaccatrosi16/go-cli-tools/internal/tty"
+	"golang.org/x/term"
+)
+
+var wrap = pkgError.WrapErrorFactory("output")
+
+func Page(content string) error {
+	if !tty.Interactive() {
+		fmt.Print(content)
+		return nil
+	}
+
+	_, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || height <= 0 {
+		fmt.Print(content)
+		return nil
+	}
+
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if len(lines) < height {
+		fmt.Print(content)
+		return nil
+	}
+
+	if pager := getPager(); len(pager) > 0 {
+		return runPager(pager, content)
+	}
+
+	return internalPager(lines, height)
+}
+
+func getPager() []string {
+	if fields := strings.Fields(os.Getenv("PAGER")); len(fields) > 0 {
+		return fields
+	}
+
+	if _, err := exec.LookPath("less"); err == nil {
+		return []string{"less", "-R", "-F", "-X"}
+	}
+
+	return nil
+}
+
+func runPager(pager []string, content string) error {
+	cmd := exec.Command(pager[0], pager[1:]...)
+	cmd.Stdin = strings.NewReader(content)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return wrap(fmt.Errorf("pager %s failed: %s", pager[0], err.Error()))
+	}
+	return nil
+}
+
+func internalPager(lines []string, height int) error {
+	fd := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return wrap(err)
+	}
+	defer term.Restore(fd, state)
+
+	// keys typed ahead belong to whatever prompt comes next
+	reader := tty.Stdin()
+	pageSize := height - 1
+	top := 0
+
+	for {
+		end := top + pageSize
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		fmt.Print("\x1b[H\x1b[2J")
+		for _, line := range lines[top:end] {
+			fmt.Printf("%s\r\n", line)
+		}
+		fmt.Printf("\x1b[7m lines %d-%d of %d (space/b: page, j/k: line, q: quit) \x1b[0m", top+1, end, len(lines))
+
+		c, err := reader.ReadByte()
+		if err != nil {
+			return wrap(err)
+		}
+
+		switch c {
+		case 'q', 3, 4:
+			fmt.Print("\r\n")
+			return nil
+		case ' ', 'f':
+			top += pageSize
+		case 'b':
+			top -= pageSize
+		case 'j', '\r', '\n':
+			top++
+		case 'k':
+			top--
+		case 'g':
+			top = 0
+		case 'G':
+			top = len(lines) - pageSize
+		case 27:
+			// arrow keys arrive as ESC [ A/B
+			if reader.Buffered() >= 2 {
+				reader.ReadByte()
+				dir, _ := reader.ReadByte()
+				if dir == 'A' {
+					top--
+				} else if dir == 'B' {
+					top++
+				}
+			} else {
+				fmt.Print("\r\n")
+				return nil
+			}
+		}
+
+		if top > len(lines)-pageSize {
+			top = len(lines) - pageSize
+		}
+		if top < 0 {
+			top = 0
+		}
+	}
+}
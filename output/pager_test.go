@@ -0,0 +1,50 @@
+//go:build !windows
+
+package output
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/lspaccatrosi16/go-cli-tools/internal/tty"
+	"github.com/lspaccatrosi16/go-cli-tools/termtest"
+)
+
+func TestInternalPager(t *testing.T) {
+	lines := []string{}
+	for i := 1; i <= 30; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+
+	s := termtest.New(t, termtest.Options{Height: 10})
+
+	var err error
+	var next string
+	s.Run(func() {
+		err = internalPager(lines, 10)
+		if err == nil {
+			next, err = tty.Stdin().ReadString('\n')
+		}
+	})
+
+	s.Expect("lines 1-9 of 30")
+	s.Press("space")
+	s.Expect("lines 10-18 of 30")
+	s.Press("down")
+	s.Expect("lines 11-19 of 30")
+	s.Type("G")
+	s.Expect("lines 22-30 of 30")
+
+	// keys typed after q belong to whatever reads stdin next
+	s.Type("qnext")
+	s.Press("enter")
+	s.Wait()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(next) != "next" {
+		t.Errorf("the next reader got %q, want next", next)
+	}
+}
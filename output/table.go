@@ -0,0 +1,260 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lspaccatrosi16/go-cli-tools/internal/tty"
+	"golang.org/x/term"
+)
+
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignRight
+)
+
+const minColumnWidth = 3
+const columnGap = "   "
+
+var ansiPattern = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+type Column struct {
+	Header   string
+	Align    Align
+	MaxWidth int
+}
+
+type TableConfig struct {
+	Border bool
+	Color  bool
+	Width  int
+}
+
+type Table struct {
+	columns []Column
+	rows    [][]string
+	config  TableConfig
+}
+
+func NewTable(config TableConfig, headers ...string) *Table {
+	columns := []Column{}
+	for _, h := range headers {
+		columns = append(columns, Column{Header: h})
+	}
+
+	return &Table{columns: columns, config: config}
+}
+
+func (t *Table) SetColumn(i int, column Column) {
+	if i < 0 || i >= len(t.columns) {
+		panic(fmt.Errorf("column %d out of range", i))
+	}
+	t.columns[i] = column
+}
+
+func (t *Table) AddRow(values ...any) {
+	row := make([]string, len(t.columns))
+	for i := range row {
+		if i < len(values) {
+			row[i] = fmt.Sprint(values[i])
+		}
+	}
+	t.rows = append(t.rows, row)
+}
+
+func (t *Table) Len() int {
+	return len(t.rows)
+}
+
+// SortBy orders rows by the given column, comparing numerically when both
+// cells are numbers.
+func (t *Table) SortBy(col int, descending bool) {
+	sort.SliceStable(t.rows, func(i, j int) bool {
+		a, b := t.rows[i][col], t.rows[j][col]
+		if descending {
+			a, b = b, a
+		}
+
+		af, aErr := strconv.ParseFloat(a, 64)
+		bf, bErr := strconv.ParseFloat(b, 64)
+		if aErr == nil && bErr == nil {
+			return af < bf
+		}
+
+		return strings.ToLower(a) < strings.ToLower(b)
+	})
+}
+
+func (t *Table) String() string {
+	buf := bytes.NewBuffer(nil)
+	t.Render(buf)
+	return buf.String()
+}
+
+func (t *Table) Page() error {
+	return Page(t.String())
+}
+
+func (t *Table) Render(w io.Writer) {
+	widths := t.fitWidths(t.naturalWidths())
+
+	if t.config.Border {
+		fmt.Fprintln(w, t.borderLine(widths, "┌", "┬", "┐"))
+	}
+
+	headers := []string{}
+	for _, c := range t.columns {
+		headers = append(headers, c.Header)
+	}
+	fmt.Fprintln(w, t.formatRow(headers, widths, true))
+
+	if t.config.Border {
+		fmt.Fprintln(w, t.borderLine(widths, "├", "┼", "┤"))
+	} else {
+		dashes := []string{}
+		for _, width := range widths {
+			dashes = append(dashes, strings.Repeat("-", width))
+		}
+		fmt.Fprintln(w, strings.Join(dashes, columnGap))
+	}
+
+	for _, row := range t.rows {
+		fmt.Fprintln(w, t.formatRow(row, widths, false))
+	}
+
+	if t.config.Border {
+		fmt.Fprintln(w, t.borderLine(widths, "└", "┴", "┘"))
+	}
+}
+
+func (t *Table) naturalWidths() []int {
+	widths := make([]int, len(t.columns))
+
+	for i, c := range t.columns {
+		widths[i] = visibleLen(c.Header)
+	}
+
+	for _, row := range t.rows {
+		for i, cell := range row {
+			if l := visibleLen(cell); l > widths[i] {
+				widths[i] = l
+			}
+		}
+	}
+
+	for i, c := range t.columns {
+		if c.MaxWidth > 0 && widths[i] > c.MaxWidth {
+			widths[i] = c.MaxWidth
+		}
+	}
+
+	return widths
+}
+
+// fitWidths shrinks the widest columns until the table fits the target width.
+func (t *Table) fitWidths(widths []int) []int {
+	limit := t.config.Width
+	if limit == 0 {
+		limit = terminalWidth()
+	}
+	if limit <= 0 {
+		return widths
+	}
+
+	for t.totalWidth(widths) > limit {
+		widest := 0
+		for i := range widths {
+			if widths[i] > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= minColumnWidth {
+			break
+		}
+		widths[widest]--
+	}
+
+	return widths
+}
+
+func (t *Table) totalWidth(widths []int) int {
+	total := 0
+	for _, w := range widths {
+		total += w
+	}
+
+	if t.config.Border {
+		return total + 3*len(widths) + 1
+	}
+	return total + len(columnGap)*(len(widths)-1)
+}
+
+func (t *Table) formatRow(cells []string, widths []int, header bool) string {
+	formatted := []string{}
+
+	for i, width := range widths {
+		cell := truncate(cells[i], width)
+		pad := strings.Repeat(" ", width-visibleLen(cell))
+
+		if t.columns[i].Align == AlignRight {
+			cell = pad + cell
+		} else {
+			cell = cell + pad
+		}
+
+		if header && t.config.Color && tty.Color() {
+			cell = "\x1b[1m" + cell + "\x1b[0m"
+		}
+
+		formatted = append(formatted, cell)
+	}
+
+	if t.config.Border {
+		return "│ " + strings.Join(formatted, " │ ") + " │"
+	}
+	return strings.TrimRight(strings.Join(formatted, columnGap), " ")
+}
+
+func (t *Table) borderLine(widths []int, left string, mid string, right string) string {
+	segments := []string{}
+	for _, w := range widths {
+		segments = append(segments, strings.Repeat("─", w+2))
+	}
+	return left + strings.Join(segments, mid) + right
+}
+
+func visibleLen(str string) int {
+	return len([]rune(ansiPattern.ReplaceAllString(str, "")))
+}
+
+func truncate(str string, width int) string {
+	if visibleLen(str) <= width {
+		return str
+	}
+
+	r := []rune(ansiPattern.ReplaceAllString(str, ""))
+	if width <= 1 {
+		return string(r[:width])
+	}
+	return string(r[:width-1]) + "…"
+}
+
+func terminalWidth() int {
+	if !tty.StdoutIsTerminal() {
+		return 0
+	}
+
+	w, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return 0
+	}
+	return w
+}
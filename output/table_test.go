@@ -0,0 +1,137 @@
+package output
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		str   string
+		width int
+		want  string
+	}{
+		{"hello", 10, "hello"},
+		{"hello", 5, "hello"},
+		{"hello", 4, "hel…"},
+		{"hello", 1, "h"},
+		{"héllo wörld", 6, "héllo…"},
+		{"\x1b[31mhello\x1b[0m", 5, "\x1b[31mhello\x1b[0m"},
+		{"\x1b[31mhello\x1b[0m", 3, "he…"},
+	}
+
+	for _, test := range tests {
+		if got := truncate(test.str, test.width); got != test.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", test.str, test.width, got, test.want)
+		}
+	}
+}
+
+func TestVisibleLen(t *testing.T) {
+	tests := []struct {
+		str  string
+		want int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"héllo", 5},
+		{"\x1b[1mbold\x1b[0m", 4},
+		{"\x1b[38;5;196mred\x1b[0m", 3},
+	}
+
+	for _, test := range tests {
+		if got := visibleLen(test.str); got != test.want {
+			t.Errorf("visibleLen(%q) = %d, want %d", test.str, got, test.want)
+		}
+	}
+}
+
+func TestFitWidths(t *testing.T) {
+	tests := []struct {
+		name   string
+		config TableConfig
+		widths []int
+		want   []int
+	}{
+		{"fits already", TableConfig{Width: 80}, []int{10, 10}, []int{10, 10}},
+		{"shrinks widest first", TableConfig{Width: 23}, []int{30, 5}, []int{15, 5}},
+		{"shrinks evenly", TableConfig{Width: 13}, []int{10, 10}, []int{5, 5}},
+		{"stops at minimum", TableConfig{Width: 5}, []int{10, 10}, []int{minColumnWidth, minColumnWidth}},
+		{"borders count", TableConfig{Width: 20, Border: true}, []int{10, 10}, []int{6, 7}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			table := NewTable(test.config, make([]string, len(test.widths))...)
+			got := table.fitWidths(append([]int{}, test.widths...))
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSortBy(t *testing.T) {
+	tests := []struct {
+		name       string
+		col        int
+		descending bool
+		want       []string
+	}{
+		{"numbers", 1, false, []string{"b", "c", "a"}},
+		{"numbers descending", 1, true, []string{"a", "c", "b"}},
+		{"text ignores case", 2, false, []string{"a", "c", "b"}},
+		{"mixed falls back to text", 3, false, []string{"c", "a", "b"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			table := NewTable(TableConfig{}, "name", "size", "owner", "mixed")
+			table.AddRow("a", 100, "alice", "x")
+			table.AddRow("b", 9.5, "Zed", "y")
+			table.AddRow("c", 20, "Bob", "10")
+			table.SortBy(test.col, test.descending)
+
+			got := []string{}
+			for _, row := range table.rows {
+				got = append(got, row[0])
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	table := NewTable(TableConfig{Width: 80}, "name", "size")
+	table.SetColumn(1, Column{Header: "size", Align: AlignRight})
+	table.AddRow("alpha", 5)
+	table.AddRow("b", 1200)
+
+	want := strings.Join([]string{
+		"name    size",
+		"-----   ----",
+		"alpha      5",
+		"b       1200",
+		"",
+	}, "\n")
+	if got := table.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	table.config.Border = true
+	want = strings.Join([]string{
+		"┌───────┬──────┐",
+		"│ name  │ size │",
+		"├───────┼──────┤",
+		"│ alpha │    5 │",
+		"│ b     │ 1200 │",
+		"└───────┴──────┘",
+		"",
+	}, "\n")
+	if got := table.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,271 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Unit int
+
+const (
+	Items Unit = iota
+	Bytes
+)
+
+const barWidth = 30
+
+type Bar struct {
+	mu      sync.Mutex
+	label   string
+	total   int64
+	current int64
+	unit    Unit
+	start   time.Time
+	end     time.Time
+	done    bool
+
+	lastPlain time.Time
+	lastStep  int64
+	reported  bool
+
+	own *renderer
+}
+
+func newBar(label string, total int64, unit Unit) *Bar {
+	return &Bar{
+		label:    label,
+		total:    total,
+		unit:     unit,
+		start:    time.Now(),
+		lastStep: -1,
+	}
+}
+
+func NewBar(label string, total int64, unit Unit) *Bar {
+	b := newBar(label, total, unit)
+	b.own = newRenderer()
+	b.own.add(b)
+	return b
+}
+
+func (b *Bar) Add(n int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.current += n
+}
+
+func (b *Bar) Set(n int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.current = n
+}
+
+func (b *Bar) Finish() {
+	b.mu.Lock()
+	b.done = true
+	b.end = time.Now()
+	b.mu.Unlock()
+
+	if b.own != nil {
+		b.own.close()
+	}
+}
+
+func (b *Bar) Reader(r io.Reader) io.Reader {
+	return &countingReader{r: r, bar: b}
+}
+
+// SectionReader reads size bytes of r and moves the bar by the parts of r
+// that have been read. Uploaders that take an io.ReaderAt read each part while
+// sending it rather than buffering it first, and data read again after a seek,
+// such as for a retried request, is only counted once.
+func (b *Bar) SectionReader(r io.ReaderAt, size int64) *io.SectionReader {
+	return io.NewSectionReader(&countingReaderAt{r: r, bar: b}, 0, size)
+}
+
+func (b *Bar) Writer(w io.Writer) io.Writer {
+	return &countingWriter{w: w, bar: b}
+}
+
+func (b *Bar) finished() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.done
+}
+
+func (b *Bar) stats() (current int64, rate float64, eta time.Duration, elapsed time.Duration) {
+	elapsed = time.Since(b.start)
+	if b.done {
+		elapsed = b.end.Sub(b.start)
+	}
+	current = b.current
+
+	if elapsed > 0 {
+		rate = float64(current) / elapsed.Seconds()
+	}
+
+	if rate > 0 && b.total > current {
+		eta = time.Duration(float64(b.total-current) / rate * float64(time.Second))
+	}
+
+	return
+}
+
+func (b *Bar) percent() int64 {
+	if b.total <= 0 {
+		return 0
+	}
+	pct := b.current * 100 / b.total
+	if pct > 100 {
+		pct = 100
+	}
+	return pct
+}
+
+func (b *Bar) summary() string {
+	current, rate, eta, elapsed := b.stats()
+
+	count := fmt.Sprintf("%s/%s", b.format(current), b.format(b.total))
+	throughput := fmt.Sprintf("%s/s", b.format(int64(rate)))
+
+	if b.done {
+		return fmt.Sprintf("%3d%% %s %s in %s", b.percent(), count, throughput, formatDuration(elapsed))
+	}
+
+	return fmt.Sprintf("%3d%% %s %s ETA %s", b.percent(), count, throughput, formatDuration(eta))
+}
+
+func (b *Bar) render(width int) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	filled := int(b.percent() * barWidth / 100)
+	bar := strings.Repeat("=", filled)
+	if filled < barWidth {
+		bar += ">" + strings.Repeat(" ", barWidth-filled-1)
+	}
+
+	return fmt.Sprintf("%s [%s] %s", b.label, bar, b.summary())
+}
+
+func (b *Bar) plain(now time.Time) (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.reported {
+		return "", false
+	}
+
+	if b.done {
+		b.reported = true
+	} else {
+		step := b.percent() / 10
+		if step == b.lastStep && now.Sub(b.lastPlain) < plainInterval {
+			return "", false
+		}
+		b.lastStep = step
+	}
+
+	b.lastPlain = now
+	return fmt.Sprintf("%s: %s", b.label, b.summary()), true
+}
+
+func (b *Bar) format(n int64) string {
+	if b.unit == Bytes {
+		return formatBytes(n)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+type countingReader struct {
+	r   io.Reader
+	bar *Bar
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.bar.Add(int64(n))
+	return n, err
+}
+
+type countingWriter struct {
+	w   io.Writer
+	bar *Bar
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.bar.Add(int64(n))
+	return n, err
+}
+
+type countingReaderAt struct {
+	r    io.ReaderAt
+	bar  *Bar
+	mu   sync.Mutex
+	read spans
+}
+
+func (c *countingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	n, err := c.r.ReadAt(p, off)
+
+	c.mu.Lock()
+	c.read = c.read.add(off, off+int64(n))
+	size := c.read.size()
+	c.mu.Unlock()
+
+	c.bar.Set(size)
+	return n, err
+}
+
+// spans is a sorted list of the [start, end) ranges that have been read.
+type spans [][2]int64
+
+func (s spans) add(start, end int64) spans {
+	if start >= end {
+		return s
+	}
+
+	out := spans{}
+	for _, span := range s {
+		if span[1] < start || span[0] > end {
+			out = append(out, span)
+			continue
+		}
+		start = min(start, span[0])
+		end = max(end, span[1])
+	}
+	out = append(out, [2]int64{start, end})
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i][0] < out[j][0]
+	})
+	return out
+}
+
+func (s spans) size() int64 {
+	total := int64(0)
+	for _, span := range s {
+		total += span[1] - span[0]
+	}
+	return total
+}
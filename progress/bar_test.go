@@ -0,0 +1,178 @@
+package progress
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{10 * 1024 * 1024, "10.0 MiB"},
+		{3 << 30, "3.0 GiB"},
+	}
+
+	for _, test := range tests {
+		if got := formatBytes(test.n); got != test.want {
+			t.Errorf("formatBytes(%d) = %q, want %q", test.n, got, test.want)
+		}
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		current, total int64
+		want           int64
+	}{
+		{0, 100, 0},
+		{50, 100, 50},
+		{1, 3, 33},
+		{150, 100, 100},
+		{10, 0, 0},
+	}
+
+	for _, test := range tests {
+		b := newBar("x", test.total, Items)
+		b.Set(test.current)
+		if got := b.percent(); got != test.want {
+			t.Errorf("%d/%d: got %d, want %d", test.current, test.total, got, test.want)
+		}
+	}
+}
+
+func TestSummary(t *testing.T) {
+	// running bars measure against the clock, so their rates are chosen to
+	// stay the same while the test runs
+	start := time.Now().Add(-10500 * time.Millisecond)
+
+	tests := []struct {
+		name    string
+		unit    Unit
+		current int64
+		total   int64
+		done    bool
+		want    string
+	}{
+		{"items running", Items, 50, 100, false, " 50% 50/100 4/s ETA 11s"},
+		{"done", Items, 100, 100, true, "100% 100/100 9/s in 11s"},
+		{"bytes done", Bytes, 21 << 20, 21 << 20, true, "100% 21.0 MiB/21.0 MiB 2.0 MiB/s in 11s"},
+		{"nothing yet", Items, 0, 100, false, "  0% 0/100 0/s ETA 0s"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := newBar("x", test.total, test.unit)
+			b.start = start
+			b.current = test.current
+			if test.done {
+				b.done = true
+				b.end = start.Add(10500 * time.Millisecond)
+			}
+
+			if got := b.summary(); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestPlainThrottling(t *testing.T) {
+	b := newBar("upload", 100, Items)
+	now := b.start
+
+	steps := []struct {
+		after   time.Duration
+		current int64
+		finish  bool
+		report  bool
+	}{
+		{0, 0, false, true},
+		{time.Second, 5, false, false},
+		{2 * time.Second, 10, false, true},
+		{3 * time.Second, 15, false, false},
+		{plainInterval + 3*time.Second, 15, false, true},
+		{plainInterval + 4*time.Second, 100, true, true},
+		{plainInterval + 5*time.Second, 100, false, false},
+	}
+
+	for i, step := range steps {
+		b.Set(step.current)
+		if step.finish {
+			b.done = true
+			b.end = now.Add(step.after)
+		}
+
+		_, reported := b.plain(now.Add(step.after))
+		if reported != step.report {
+			t.Errorf("step %d: reported %v, want %v", i, reported, step.report)
+		}
+	}
+}
+
+func TestSpansAdd(t *testing.T) {
+	type span = [2]int64
+
+	tests := []struct {
+		name string
+		adds []span
+		want spans
+		size int64
+	}{
+		{"single", []span{{0, 10}}, spans{{0, 10}}, 10},
+		{"empty ignored", []span{{5, 5}}, spans{}, 0},
+		{"disjoint sorted", []span{{20, 30}, {0, 10}}, spans{{0, 10}, {20, 30}}, 20},
+		{"overlap merges", []span{{0, 10}, {5, 15}}, spans{{0, 15}}, 15},
+		{"adjacent merges", []span{{0, 10}, {10, 20}}, spans{{0, 20}}, 20},
+		{"reread counted once", []span{{0, 10}, {0, 10}, {2, 8}}, spans{{0, 10}}, 10},
+		{"bridges gap", []span{{0, 5}, {10, 15}, {4, 11}}, spans{{0, 15}}, 15},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := spans{}
+			for _, add := range test.adds {
+				s = s.add(add[0], add[1])
+			}
+			if !reflect.DeepEqual(s, test.want) {
+				t.Errorf("got %v, want %v", s, test.want)
+			}
+			if got := s.size(); got != test.size {
+				t.Errorf("size %d, want %d", got, test.size)
+			}
+		})
+	}
+}
+
+func TestSectionReaderCountsOnce(t *testing.T) {
+	data := bytes.Repeat([]byte("x"), 1000)
+	b := newBar("x", int64(len(data)), Bytes)
+	r := b.SectionReader(bytes.NewReader(data), int64(len(data)))
+
+	buf := make([]byte, 400)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		t.Fatal(err)
+	}
+	if b.current != 400 {
+		t.Fatalf("got %d after the first read, want 400", b.current)
+	}
+
+	// a retried request rewinds and reads everything again
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		t.Fatal(err)
+	}
+	if b.current != 1000 {
+		t.Errorf("got %d after reading twice, want 1000", b.current)
+	}
+}
@@ -0,0 +1,188 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lspaccatrosi16/go-cli-tools/internal/tty"
+	"golang.org/x/term"
+)
+
+const LINE_UP = "\033[1A"
+const LINE_CLEAR = "\x1b[2K"
+
+const refreshInterval = 100 * time.Millisecond
+const plainInterval = 5 * time.Second
+
+type line interface {
+	render(width int) string
+	plain(now time.Time) (string, bool)
+	finished() bool
+}
+
+type renderer struct {
+	mu     sync.Mutex
+	lines  []line
+	out    io.Writer
+	tty    bool
+	drawn  int
+	stop   chan struct{}
+	done   chan struct{}
+	closed bool
+}
+
+func newRenderer() *renderer {
+	r := &renderer{
+		out:  os.Stdout,
+		tty:  tty.StdoutIsTerminal() && !tty.Accessible(),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	go r.loop()
+
+	return r
+}
+
+func (r *renderer) add(l line) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lines = append(r.lines, l)
+}
+
+func (r *renderer) loop() {
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+	defer close(r.done)
+
+	for {
+		select {
+		case <-ticker.C:
+			r.draw()
+		case <-r.stop:
+			r.draw()
+			return
+		}
+	}
+}
+
+func (r *renderer) width() int {
+	if !r.tty {
+		return 80
+	}
+
+	w, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || w <= 0 {
+		return 80
+	}
+	return w
+}
+
+func (r *renderer) draw() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.tty {
+		now := time.Now()
+		for _, l := range r.lines {
+			if str, ok := l.plain(now); ok {
+				fmt.Fprintln(r.out, str)
+			}
+		}
+		return
+	}
+
+	buf := strings.Builder{}
+	for i := 0; i < r.drawn; i++ {
+		buf.WriteString(LINE_UP)
+		buf.WriteString(LINE_CLEAR)
+	}
+
+	width := r.width()
+	for _, l := range r.lines {
+		buf.WriteString("\r")
+		buf.WriteString(truncate(l.render(width), width))
+		buf.WriteString("\n")
+	}
+
+	r.drawn = len(r.lines)
+	fmt.Fprint(r.out, buf.String())
+}
+
+func (r *renderer) allFinished() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, l := range r.lines {
+		if !l.finished() {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *renderer) close() {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return
+	}
+	r.closed = true
+	r.mu.Unlock()
+
+	close(r.stop)
+	<-r.done
+}
+
+type Multi struct {
+	r *renderer
+}
+
+func NewMulti() *Multi {
+	return &Multi{r: newRenderer()}
+}
+
+func (m *Multi) AddBar(label string, total int64, unit Unit) *Bar {
+	b := newBar(label, total, unit)
+	m.r.add(b)
+	return b
+}
+
+func (m *Multi) AddSpinner(label string) *Spinner {
+	s := newSpinner(label)
+	m.r.add(s)
+	return s
+}
+
+func (m *Multi) Wait() {
+	for !m.r.allFinished() {
+		time.Sleep(refreshInterval)
+	}
+	m.r.close()
+}
+
+func (m *Multi) Stop() {
+	m.r.close()
+}
+
+func truncate(str string, width int) string {
+	r := []rune(str)
+	if len(r) <= width {
+		return str
+	}
+	if width <= 1 {
+		return string(r[:width])
+	}
+	return string(r[:width-1]) + "…"
+}
+
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return "0s"
+	}
+	return d.Round(time.Second).String()
+}
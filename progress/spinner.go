@@ -0,0 +1,98 @@
+package progress
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+type Spinner struct {
+	mu      sync.Mutex
+	label   string
+	message string
+	start   time.Time
+	end     time.Time
+	done    bool
+
+	lastPlain time.Time
+	reported  bool
+
+	own *renderer
+}
+
+func newSpinner(label string) *Spinner {
+	return &Spinner{label: label, start: time.Now()}
+}
+
+func NewSpinner(label string) *Spinner {
+	s := newSpinner(label)
+	s.own = newRenderer()
+	s.own.add(s)
+	return s
+}
+
+func (s *Spinner) SetLabel(label string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.label = label
+}
+
+func (s *Spinner) Stop(message string) {
+	s.mu.Lock()
+	s.done = true
+	s.end = time.Now()
+	s.message = message
+	if s.message == "" {
+		s.message = "done"
+	}
+	s.mu.Unlock()
+
+	if s.own != nil {
+		s.own.close()
+	}
+}
+
+func (s *Spinner) finished() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.done
+}
+
+func (s *Spinner) render(width int) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elapsed := time.Since(s.start)
+
+	if s.done {
+		return fmt.Sprintf("✓ %s: %s (%s)", s.label, s.message, formatDuration(s.end.Sub(s.start)))
+	}
+
+	frame := spinnerFrames[int(elapsed/refreshInterval)%len(spinnerFrames)]
+	return fmt.Sprintf("%s %s (%s)", frame, s.label, formatDuration(elapsed))
+}
+
+func (s *Spinner) plain(now time.Time) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.reported {
+		return "", false
+	}
+
+	if s.done {
+		s.reported = true
+		return fmt.Sprintf("%s: %s (%s)", s.label, s.message, formatDuration(s.end.Sub(s.start))), true
+	}
+
+	elapsed := now.Sub(s.start)
+
+	if !s.lastPlain.IsZero() && now.Sub(s.lastPlain) < plainInterval {
+		return "", false
+	}
+
+	s.lastPlain = now
+	return fmt.Sprintf("%s... (%s)", s.label, formatDuration(elapsed)), true
+}
@@ -0,0 +1,215 @@
+package structconfig
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/lspaccatrosi16/go-cli-tools/input"
+)
+
+func editPointer(n *node) func() error {
+	return func() error {
+		if n.Value.IsNil() {
+			n.Value.Set(reflect.New(n.Value.Type().Elem()))
+		}
+		return editValue(n.label(), n.Value)
+	}
+}
+
+func editSlice(n *node) func() error {
+	return func() error {
+		v := n.Value
+		resizable := v.Kind() == reflect.Slice
+
+		for {
+			items := []input.SelectOption{{Name: "Back", Value: "back"}}
+			for i := 0; i < v.Len(); i++ {
+				items = append(items, input.SelectOption{Name: fmt.Sprintf("[%d] : %s", i, formatValue(v.Index(i))), Value: strconv.Itoa(i)})
+			}
+			if resizable {
+				items = append(items, input.SelectOption{Name: "Add element", Value: "add"})
+				if v.Len() > 0 {
+					items = append(items, input.SelectOption{Name: "Remove element", Value: "remove"})
+				}
+				if v.Len() > 1 {
+					items = append(items, input.SelectOption{Name: "Move element", Value: "move"})
+				}
+			}
+
+			selected, err := input.GetSearchableSelection(fmt.Sprintf("Edit %s", n.label()), items)
+			if err != nil {
+				return err
+			}
+
+			switch selected {
+			case "back":
+				if err := n.Options.validate(v); err != nil {
+					fmt.Printf("ERROR: %s\n", err.Error())
+					continue
+				}
+				return nil
+			case "add":
+				elem := reflect.New(v.Type().Elem()).Elem()
+				if err := editValue(fmt.Sprintf("[%d]", v.Len()), elem); err != nil {
+					return err
+				}
+				v.Set(reflect.Append(v, elem))
+			case "remove":
+				idx, err := pickIndex("Element to remove", v, -1)
+				if err != nil {
+					return err
+				}
+				v.Set(removeIndex(v, idx))
+			case "move":
+				idx, err := pickIndex("Element to move", v, -1)
+				if err != nil {
+					return err
+				}
+				to, err := pickIndex("Move to position", v, idx)
+				if err != nil {
+					return err
+				}
+				elem := reflect.New(v.Type().Elem()).Elem()
+				elem.Set(v.Index(idx))
+				v.Set(insertIndex(removeIndex(v, idx), to, elem))
+			default:
+				idx, _ := strconv.Atoi(selected)
+				if err := editValue(fmt.Sprintf("[%d]", idx), v.Index(idx)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+func pickIndex(label string, v reflect.Value, current int) (int, error) {
+	items := []input.SelectOption{}
+	for i := 0; i < v.Len(); i++ {
+		items = append(items, input.SelectOption{Name: fmt.Sprintf("[%d] : %s", i, formatValue(v.Index(i))), Value: strconv.Itoa(i)})
+	}
+
+	config := input.SelectConfig{}
+	if current != -1 {
+		config.Default = strconv.Itoa(current)
+	}
+
+	_, idx, err := input.GetSelectionWithConfig(label, items, config)
+	return idx, err
+}
+
+func removeIndex(v reflect.Value, idx int) reflect.Value {
+	out := reflect.MakeSlice(v.Type(), 0, v.Len()-1)
+	out = reflect.AppendSlice(out, v.Slice(0, idx))
+	return reflect.AppendSlice(out, v.Slice(idx+1, v.Len()))
+}
+
+func insertIndex(v reflect.Value, idx int, elem reflect.Value) reflect.Value {
+	out := reflect.MakeSlice(v.Type(), 0, v.Len()+1)
+	out = reflect.AppendSlice(out, v.Slice(0, idx))
+	out = reflect.Append(out, elem)
+	return reflect.AppendSlice(out, v.Slice(idx, v.Len()))
+}
+
+func editMap(n *node) func() error {
+	return func() error {
+		v := n.Value
+
+		for {
+			keys := sortedKeys(v)
+
+			items := []input.SelectOption{{Name: "Back", Value: "back"}}
+			for i, key := range keys {
+				items = append(items, input.SelectOption{Name: fmt.Sprintf("%s : %s", formatValue(key), formatValue(v.MapIndex(key))), Value: strconv.Itoa(i)})
+			}
+			items = append(items, input.SelectOption{Name: "Add entry", Value: "add"})
+			if len(keys) > 0 {
+				items = append(items, input.SelectOption{Name: "Remove entry", Value: "remove"})
+			}
+
+			selected, err := input.GetSearchableSelection(fmt.Sprintf("Edit %s", n.label()), items)
+			if err != nil {
+				return err
+			}
+
+			switch selected {
+			case "back":
+				if err := n.Options.validate(v); err != nil {
+					fmt.Printf("ERROR: %s\n", err.Error())
+					continue
+				}
+				return nil
+			case "add":
+				key, err := readMapKey(v)
+				if err != nil {
+					return err
+				}
+				elem := reflect.New(v.Type().Elem()).Elem()
+				if err := editValue(formatValue(key), elem); err != nil {
+					return err
+				}
+				if v.IsNil() {
+					v.Set(reflect.MakeMap(v.Type()))
+				}
+				v.SetMapIndex(key, elem)
+			case "remove":
+				options := []input.SelectOption{}
+				for i, key := range keys {
+					options = append(options, input.SelectOption{Name: formatValue(key), Value: strconv.Itoa(i)})
+				}
+				_, idx, err := input.GetSelectionWithConfig("Entry to remove", options, input.SelectConfig{})
+				if err != nil {
+					return err
+				}
+				v.SetMapIndex(keys[idx], reflect.Value{})
+			default:
+				idx, _ := strconv.Atoi(selected)
+				// map values are not addressable, so edit a copy and store it back
+				elem := reflect.New(v.Type().Elem()).Elem()
+				elem.Set(v.MapIndex(keys[idx]))
+				if err := editValue(formatValue(keys[idx]), elem); err != nil {
+					return err
+				}
+				v.SetMapIndex(keys[idx], elem)
+			}
+		}
+	}
+}
+
+func readMapKey(v reflect.Value) (reflect.Value, error) {
+	keyType := v.Type().Key()
+
+	parse := func(str string) (reflect.Value, error) {
+		key, err := parseValue(keyType, str)
+		if err != nil {
+			return key, err
+		}
+		if v.MapIndex(key).IsValid() {
+			return key, fmt.Errorf("key %s already exists", str)
+		}
+		return key, nil
+	}
+
+	return input.GetParsedInput("New key", nil, formatValue, parse)
+}
+
+func sortedKeys(v reflect.Value) []reflect.Value {
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		switch {
+		case a.CanInt():
+			return a.Int() < b.Int()
+		case a.CanUint():
+			return a.Uint() < b.Uint()
+		case a.CanFloat():
+			return a.Float() < b.Float()
+		case a.Kind() == reflect.Bool:
+			return !a.Bool() && b.Bool()
+		default:
+			return a.String() < b.String()
+		}
+	})
+	return keys
+}
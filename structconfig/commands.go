@@ -0,0 +1,215 @@
+package structconfig
+
+import (
+	"fmt"
+
+	"github.com/lspaccatrosi16/go-cli-tools/args"
+	"github.com/lspaccatrosi16/go-cli-tools/command"
+	"github.com/lspaccatrosi16/go-cli-tools/config"
+	"github.com/lspaccatrosi16/go-cli-tools/input"
+)
+
+// RegisterCommands adds a command called name to m for working with cfg.
+// When the positional arguments contain name followed by a subcommand it runs
+// without prompting:
+//
+//	app config get Server.Port
+//	app config set Server.Port 8080
+//	app config list
+//	app config edit
+//
+// Without a subcommand it shows a menu of the same actions. save is called
+// after every change and may be nil. Scripts should run the command with
+// m.RunWithError, which returns failures such as an invalid value instead of
+// printing them, so that the program can exit with a non-zero status.
+func RegisterCommands[T any](m *command.Manager, name string, cfg *T, save func() error) {
+	if cfg == nil {
+		panic("input is nil pointer")
+	}
+
+	m.Register(name, "get, set, list or edit the configuration", func() error {
+		rest := argsAfter(name)
+		if len(rest) == 0 {
+			return configMenu(cfg, save, nil)
+		}
+		return runConfigCommand(cfg, save, nil, rest)
+	})
+}
+
+// RegisterLayeredCommands is RegisterCommands for a config read with
+// config.ReadLayeredConfig. It adds a where subcommand that shows which layer
+// set a value:
+//
+//	app config where Server.Port
+//
+// Changes are made to the merged layered.Value, so save should normally be
+// layered.SaveUser, which writes only the changed values to the user file.
+// Writing all of layered.Value would copy every other layer into it.
+func RegisterLayeredCommands[T any](m *command.Manager, name string, layered *config.Layered[T], save func() error) {
+	if layered == nil {
+		panic("input is nil pointer")
+	}
+
+	m.Register(name, "get, set, list, edit or trace the configuration", func() error {
+		rest := argsAfter(name)
+		if len(rest) == 0 {
+			return configMenu(&layered.Value, save, layered.Where)
+		}
+		return runConfigCommand(&layered.Value, save, layered.Where, rest)
+	})
+}
+
+func argsAfter(name string) []string {
+	positional := args.GetArgs()
+	for i, arg := range positional {
+		if arg == name {
+			return positional[i+1:]
+		}
+	}
+	return nil
+}
+
+// where is nil unless the config was read in layers.
+func runConfigCommand[T any](cfg *T, save func() error, where func(string) config.Source, rest []string) error {
+	usage := func(format string) error {
+		return fmt.Errorf("usage: %s", format)
+	}
+
+	switch rest[0] {
+	case "get":
+		if len(rest) != 2 {
+			return usage("get <path>")
+		}
+		value, err := Get(cfg, rest[1])
+		if err != nil {
+			return err
+		}
+		fmt.Println(value)
+	case "set":
+		if len(rest) != 3 {
+			return usage("set <path> <value>")
+		}
+		if err := Set(cfg, rest[1], rest[2]); err != nil {
+			return err
+		}
+		return saveConfig(save)
+	case "list":
+		printEntries(List(cfg))
+	case "edit":
+		return editConfig(cfg, save)
+	case "where":
+		if where == nil {
+			return unknownSubcommand(rest[0], where)
+		}
+		if len(rest) != 2 {
+			return usage("where <path>")
+		}
+		return printWhere(cfg, where, rest[1])
+	default:
+		return unknownSubcommand(rest[0], where)
+	}
+
+	return nil
+}
+
+func unknownSubcommand(name string, where func(string) config.Source) error {
+	if where != nil {
+		return fmt.Errorf("unknown subcommand %q, expected get, set, list, edit or where", name)
+	}
+	return fmt.Errorf("unknown subcommand %q, expected get, set, list or edit", name)
+}
+
+func configMenu[T any](cfg *T, save func() error, where func(string) config.Source) error {
+	for {
+		manager := command.NewManager(command.ManagerConfig{})
+
+		manager.Register("get", "show a single value", func() error {
+			path, err := pickPath(cfg)
+			if err != nil {
+				return err
+			}
+			value, err := Get(cfg, path)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s : %s\n", path, value)
+			return nil
+		})
+
+		manager.Register("set", "change a single value", func() error {
+			path, err := pickPath(cfg)
+			if err != nil {
+				return err
+			}
+			input.GetValidatedInput("New value", func(str string) error {
+				return Set(cfg, path, str)
+			})
+			return saveConfig(save)
+		})
+
+		manager.Register("list", "show every value", func() error {
+			printEntries(List(cfg))
+			return nil
+		})
+
+		manager.Register("edit", "edit the configuration", func() error {
+			return editConfig(cfg, save)
+		})
+
+		if where != nil {
+			manager.Register("where", "show which layer set a value", func() error {
+				path, err := pickPath(cfg)
+				if err != nil {
+					return err
+				}
+				return printWhere(cfg, where, path)
+			})
+		}
+
+		if manager.Tui() {
+			return nil
+		}
+	}
+}
+
+func pickPath[T any](cfg *T) (string, error) {
+	items := []input.SelectOption{}
+	for _, entry := range List(cfg) {
+		items = append(items, input.SelectOption{Name: fmt.Sprintf("%s : %s", entry.Path, entry.Value), Value: entry.Path})
+	}
+	return input.GetSearchableSelection("Select a value", items)
+}
+
+func editConfig[T any](cfg *T, save func() error) error {
+	changed, err := NewConfig[T]().Run(cfg)
+	if err != nil || !changed {
+		return err
+	}
+	return saveConfig(save)
+}
+
+func printWhere[T any](cfg *T, where func(string) config.Source, path string) error {
+	value, err := Get(cfg, path)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s : %s (set by %s)\n", path, value, where(path))
+	return nil
+}
+
+func saveConfig(save func() error) error {
+	if save == nil {
+		return nil
+	}
+	return save()
+}
+
+func printEntries(entries []Entry) {
+	width := 0
+	for _, entry := range entries {
+		width = max(width, len(entry.Path))
+	}
+	for _, entry := range entries {
+		fmt.Printf("%-*s : %s\n", width, entry.Path, entry.Value)
+	}
+}
@@ -0,0 +1,95 @@
+package structconfig
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type pointerConfig struct {
+	Count *int
+	Name  *string
+	When  *time.Time
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestPointerDefaults(t *testing.T) {
+	when := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		value    pointerConfig
+		defaults pointerConfig
+		differs  []bool
+		reset    pointerConfig
+	}{
+		{
+			name:     "both set and equal",
+			value:    pointerConfig{ptr(1), ptr("a"), ptr(when)},
+			defaults: pointerConfig{ptr(1), ptr("a"), ptr(when)},
+			differs:  []bool{false, false, false},
+			reset:    pointerConfig{ptr(1), ptr("a"), ptr(when)},
+		},
+		{
+			name:     "both set and different",
+			value:    pointerConfig{ptr(2), ptr("b"), ptr(when.Add(time.Hour))},
+			defaults: pointerConfig{ptr(1), ptr("a"), ptr(when)},
+			differs:  []bool{true, true, true},
+			reset:    pointerConfig{ptr(1), ptr("a"), ptr(when)},
+		},
+		{
+			name:     "set with nil defaults",
+			value:    pointerConfig{ptr(2), ptr("b"), ptr(when)},
+			defaults: pointerConfig{},
+			differs:  []bool{true, true, true},
+			reset:    pointerConfig{},
+		},
+		{
+			name:     "nil with set defaults",
+			value:    pointerConfig{},
+			defaults: pointerConfig{ptr(1), ptr("a"), ptr(when)},
+			differs:  []bool{true, true, true},
+			reset:    pointerConfig{ptr(1), ptr("a"), ptr(when)},
+		},
+		{
+			name:     "both nil",
+			value:    pointerConfig{},
+			defaults: pointerConfig{},
+			differs:  []bool{false, false, false},
+			reset:    pointerConfig{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := test.value
+
+			c := NewConfig[pointerConfig]()
+			c.SetDefaults(test.defaults)
+			c.Analyze(&cfg)
+
+			// describing every field used to panic on pointers to scalars
+			_ = c.tree.String()
+			makeManager(c.tree)
+
+			for i, child := range c.tree.Children {
+				if got := child.differs(); got != test.differs[i] {
+					t.Errorf("%s: differs = %v, want %v", child.FieldName, got, test.differs[i])
+				}
+				if child.differs() {
+					child.reset()
+				}
+				if child.differs() {
+					t.Errorf("%s: still differs after a reset", child.FieldName)
+				}
+			}
+
+			if changes := diffValues("", fieldOptions{}, reflect.ValueOf(test.reset), reflect.ValueOf(cfg)); len(changes) > 0 {
+				t.Errorf("after reset got changes %v", changes)
+			}
+		})
+	}
+}
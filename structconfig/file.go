@@ -0,0 +1,101 @@
+package structconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+
+	"github.com/lspaccatrosi16/go-cli-tools/config"
+	"github.com/lspaccatrosi16/go-cli-tools/storage"
+)
+
+// EditFile loads the config at path, or defaults when the file does not exist
+// yet, and opens the editor. The result is saved only when it changed and
+// passes validation, after the previous file has been copied to path+".bak".
+// It returns the config and whether it was saved.
+func EditFile[T any](path string, defaults T) (T, bool, error) {
+	defaultJson, err := json.Marshal(defaults)
+	if err != nil {
+		return defaults, false, wrap(err)
+	}
+
+	// files that fail validation are opened so they can be fixed
+	cfg, err := config.ReadConfigFileWithConfig[T](path, defaultJson, config.ReadConfig{SkipValidate: true})
+	if err != nil {
+		return defaults, false, wrap(err)
+	}
+
+	changed, err := editValid(&cfg, defaults)
+	if err != nil || !changed {
+		return cfg, false, err
+	}
+
+	// the previous file is kept as path+".bak"
+	if err := config.WriteConfigFile(path, cfg); err != nil {
+		return cfg, false, wrap(err)
+	}
+
+	return cfg, true, nil
+}
+
+// EditCloudFile is EditFile for a config stored under key in bucket. The
+// previous version is uploaded to key+".bak" before saving.
+func EditCloudFile[T any](bucket storage.StorageProvider, key string, defaults T) (T, bool, error) {
+	previous, err := bucket.GetFile(key)
+	exists := err == nil
+	if err != nil {
+		// providers do not agree on a not found error, so check the listing
+		keys, listErr := bucket.ListKeys()
+		if listErr != nil || contains(keys, key) {
+			return defaults, false, wrap(err)
+		}
+	}
+
+	cfg := defaults
+	if exists {
+		cfg, err = config.ReadCloudConfigFileWithConfig[T](bucket, key, config.ReadConfig{SkipValidate: true})
+		if err != nil {
+			return defaults, false, wrap(err)
+		}
+	}
+
+	changed, err := editValid(&cfg, defaults)
+	if err != nil || !changed {
+		return cfg, false, err
+	}
+
+	if exists {
+		if err := bucket.UploadFile(key+".bak", previous); err != nil {
+			return cfg, false, wrap(err)
+		}
+	}
+
+	if err := config.WritCloudConfigFile(bucket, key, cfg); err != nil {
+		return cfg, false, wrap(err)
+	}
+
+	return cfg, true, nil
+}
+
+// editValid runs the editor with defaults available for resetting fields. The
+// editor does not offer to save while validation fails.
+func editValid[T any](cfg *T, defaults T) (bool, error) {
+	editor := NewConfig[T]()
+	editor.SetDefaults(defaults)
+	return editor.Run(cfg)
+}
+
+// Validate checks every value in s against its sc tags, enum options and
+// Validate methods.
+func Validate[T any](s *T) error {
+	if s == nil {
+		panic("input is nil pointer")
+	}
+
+	errs := validateAll(reflect.ValueOf(s).Elem(), fieldOptions{}, "")
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return wrap(errors.Join(errs...))
+}
@@ -0,0 +1,72 @@
+package structconfig
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type opaqueConfig struct {
+	Name   string
+	Extra  map[string]any
+	Value  any
+	Items  []any
+	Nested struct {
+		Raw any
+	}
+}
+
+func TestOpaqueFields(t *testing.T) {
+	cfg := opaqueConfig{
+		Name:  "a",
+		Extra: map[string]any{"k": []any{1.0, "x"}},
+		Value: 3.5,
+		Items: []any{true},
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"Extra", `{"k":[1,"x"]}`},
+		{"Value", "3.5"},
+		{"Items", "[true]"},
+		{"Nested.Raw", "null"},
+		{"Name", "a"},
+	}
+
+	for _, test := range tests {
+		got, err := Get(&cfg, test.path)
+		if err != nil {
+			t.Fatalf("%s: %v", test.path, err)
+		}
+		if got != test.want {
+			t.Errorf("%s: got %s, want %s", test.path, got, test.want)
+		}
+	}
+
+	for _, path := range []string{"Extra", "Extra[k]", "Value", "Items[0]", "Nested.Raw"} {
+		if err := Set(&cfg, path, "1"); err == nil {
+			t.Errorf("%s: expected an error setting an opaque value", path)
+		}
+	}
+	if err := Set(&cfg, "Name", "b"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(List(&cfg)) != 5 {
+		t.Errorf("got %d entries, want 5", len(List(&cfg)))
+	}
+	if err := Validate(&cfg); err != nil {
+		t.Error(err)
+	}
+	if Table(&cfg, &opaqueConfig{}).String() == "" {
+		t.Error("empty table")
+	}
+	NewConfig[opaqueConfig]().Analyze(&cfg)
+
+	schema := JSONSchema(opaqueConfig{})
+	data, _ := json.Marshal(cfg)
+	if err := schema.Validate(data); err != nil {
+		t.Error(err)
+	}
+}
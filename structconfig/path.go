@@ -0,0 +1,375 @@
+package structconfig
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/lspaccatrosi16/go-cli-tools/internal/pkgError"
+)
+
+var wrap = pkgError.WrapErrorFactory("structconfig")
+
+// Entry is a single value in a config struct, addressed by a path such as
+// "Server.Port", "Hosts[0]" or "Limits[cpu]".
+type Entry struct {
+	Path  string
+	Type  string
+	Value string
+}
+
+type segment struct {
+	name    string
+	index   string
+	isIndex bool
+}
+
+func parsePath(path string) ([]segment, error) {
+	segments := []segment{}
+	current := strings.Builder{}
+
+	flush := func() {
+		if current.Len() > 0 {
+			segments = append(segments, segment{name: current.String()})
+			current.Reset()
+		}
+	}
+
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '.':
+			flush()
+		case '[':
+			flush()
+			end := strings.IndexByte(path[i:], ']')
+			if end == -1 {
+				return nil, fmt.Errorf("unclosed [ in path %q", path)
+			}
+			segments = append(segments, segment{index: path[i+1 : i+end], isIndex: true})
+			i += end
+		case ']':
+			return nil, fmt.Errorf("unexpected ] in path %q", path)
+		default:
+			current.WriteByte(path[i])
+		}
+	}
+	flush()
+
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("path must not be empty")
+	}
+
+	return segments, nil
+}
+
+// findField looks a field up by name among the fields makeTree exposes, so
+// hidden and unexported fields cannot be reached.
+func findField(v reflect.Value, name string) (*node, bool) {
+	if v.Kind() != reflect.Struct || isTextType(v.Type()) {
+		return nil, false
+	}
+
+	for _, child := range makeTree(v).Children {
+		if strings.EqualFold(child.FieldName, name) {
+			return child, true
+		}
+	}
+
+	return nil, false
+}
+
+// inherit passes the restrictions of a field on to everything inside it.
+func inherit(parent fieldOptions, child fieldOptions) fieldOptions {
+	child.ReadOnly = child.ReadOnly || parent.ReadOnly
+	child.Secret = child.Secret || parent.Secret
+	return child
+}
+
+func sliceIndex(v reflect.Value, seg segment, allowAppend bool) (int, error) {
+	idx, err := strconv.Atoi(seg.index)
+	limit := v.Len()
+	if allowAppend && v.Kind() == reflect.Slice {
+		limit++
+	}
+	if err != nil || idx < 0 || idx >= limit {
+		return 0, fmt.Errorf("index %s is out of range", seg.index)
+	}
+	return idx, nil
+}
+
+func lookup(v reflect.Value, opts fieldOptions, segments []segment) (reflect.Value, fieldOptions, error) {
+	for _, seg := range segments {
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return v, opts, fmt.Errorf("value is not set")
+			}
+			v = v.Elem()
+		}
+
+		if !seg.isIndex {
+			child, ok := findField(v, seg.name)
+			if !ok {
+				return v, opts, fmt.Errorf("no field named %s", seg.name)
+			}
+			v, opts = child.Value, inherit(opts, child.Options)
+			continue
+		}
+
+		opts = inherit(opts, fieldOptions{})
+
+		switch v.Kind() {
+		case reflect.Slice, reflect.Array:
+			idx, err := sliceIndex(v, seg, false)
+			if err != nil {
+				return v, opts, err
+			}
+			v = v.Index(idx)
+		case reflect.Map:
+			key, err := parseValue(v.Type().Key(), seg.index)
+			if err != nil {
+				return v, opts, err
+			}
+			elem := v.MapIndex(key)
+			if !elem.IsValid() {
+				return v, opts, fmt.Errorf("no entry for %s", seg.index)
+			}
+			v = elem
+		default:
+			return v, opts, fmt.Errorf("%s cannot be indexed", v.Kind())
+		}
+	}
+
+	return v, opts, nil
+}
+
+func assign(v reflect.Value, opts fieldOptions, segments []segment, value string) error {
+	if isOpaque(v.Type()) {
+		return fmt.Errorf("a %s cannot be set by path", v.Type())
+	}
+
+	if v.Kind() == reflect.Pointer && !isTextType(v.Type()) {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return assign(v.Elem(), opts, segments, value)
+	}
+
+	if len(segments) == 0 {
+		if opts.ReadOnly {
+			return fmt.Errorf("value is read only")
+		}
+		if !isScalarVal(v) && !isTextType(v.Type()) {
+			return fmt.Errorf("a %s cannot be set from a single value", v.Kind())
+		}
+
+		parsed, err := parseValue(v.Type(), value)
+		if err != nil {
+			return err
+		}
+		if err := opts.validate(parsed); err != nil {
+			return err
+		}
+		if options := enumOptions(parsed); len(options) > 0 && !contains(options, formatValue(parsed)) {
+			return fmt.Errorf("value must be one of %s", strings.Join(options, ", "))
+		}
+
+		v.Set(parsed)
+		return nil
+	}
+
+	seg, rest := segments[0], segments[1:]
+
+	if !seg.isIndex {
+		child, ok := findField(v, seg.name)
+		if !ok {
+			return fmt.Errorf("no field named %s", seg.name)
+		}
+		return assign(child.Value, inherit(opts, child.Options), rest, value)
+	}
+
+	inner := inherit(opts, fieldOptions{})
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		idx, err := sliceIndex(v, seg, true)
+		if err != nil {
+			return err
+		}
+		if idx == v.Len() {
+			if opts.ReadOnly {
+				return fmt.Errorf("value is read only")
+			}
+			v.Set(reflect.Append(v, reflect.New(v.Type().Elem()).Elem()))
+		}
+		return assign(v.Index(idx), inner, rest, value)
+	case reflect.Map:
+		key, err := parseValue(v.Type().Key(), seg.index)
+		if err != nil {
+			return err
+		}
+
+		// map values are not addressable, so set a copy and store it back
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if existing := v.MapIndex(key); existing.IsValid() {
+			elem.Set(existing)
+		} else if opts.ReadOnly {
+			return fmt.Errorf("value is read only")
+		}
+
+		if err := assign(elem, inner, rest, value); err != nil {
+			return err
+		}
+
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		v.SetMapIndex(key, elem)
+		return nil
+	default:
+		return fmt.Errorf("%s cannot be indexed", v.Kind())
+	}
+}
+
+func contains(options []string, value string) bool {
+	for _, option := range options {
+		if option == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Get returns the value at path, e.g. "Server.Port", formatted the same way
+// as in the editor.
+func Get[T any](s *T, path string) (string, error) {
+	if s == nil {
+		panic("input is nil pointer")
+	}
+
+	segments, err := parsePath(path)
+	if err != nil {
+		return "", wrap(err)
+	}
+
+	v, _, err := lookup(reflect.ValueOf(s).Elem(), fieldOptions{}, segments)
+	if err != nil {
+		return "", wrap(fmt.Errorf("%s: %s", path, err.Error()))
+	}
+
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return "<nil>", nil
+	}
+	for v.Kind() == reflect.Pointer && !isTextType(v.Type()) {
+		v = v.Elem()
+	}
+
+	return formatValue(v), nil
+}
+
+// Set parses value with the same rules as the editor and stores it at path.
+// Nil pointers along the way are allocated, an index one past the end of a
+// slice appends and a missing map key adds an entry. Nothing is changed if the
+// value is rejected, either by the sc tags of its field or because the config
+// would fail validation in a way it did not before.
+func Set[T any](s *T, path string, value string) error {
+	return NewConfig[T]().Set(s, path, value)
+}
+
+// Set is the package level Set, also running the validators added with
+// AddValidator.
+func (c *ConfigOutput[T]) Set(s *T, path string, value string) error {
+	if s == nil {
+		panic("input is nil pointer")
+	}
+
+	segments, err := parsePath(path)
+	if err != nil {
+		return wrap(err)
+	}
+
+	original := reflect.ValueOf(s).Elem()
+	working := deepCopy(original)
+
+	if err := assign(working, fieldOptions{}, segments, value); err != nil {
+		return wrap(fmt.Errorf("%s: %s", path, err.Error()))
+	}
+
+	// problems that were already there are left for the user to fix one
+	// value at a time
+	if err := newProblems(c.problems(original), c.problems(working)); err != nil {
+		return wrap(err)
+	}
+
+	original.Set(working)
+	return nil
+}
+
+func newProblems(before []error, after []error) error {
+	known := map[string]bool{}
+	for _, err := range before {
+		known[err.Error()] = true
+	}
+
+	errs := []error{}
+	for _, err := range after {
+		if !known[err.Error()] {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// List returns every value in s with its path. Secret values are masked.
+func List[T any](s *T) []Entry {
+	if s == nil {
+		panic("input is nil pointer")
+	}
+
+	entries := []Entry{}
+	collect(reflect.ValueOf(s).Elem(), fieldOptions{}, "", &entries)
+	return entries
+}
+
+func collect(v reflect.Value, opts fieldOptions, path string, entries *[]Entry) {
+	show := func(v reflect.Value) string {
+		if opts.Secret {
+			return "********"
+		}
+		return formatValue(v)
+	}
+
+	if v.Kind() == reflect.Pointer && !isTextType(v.Type()) {
+		if v.IsNil() {
+			*entries = append(*entries, Entry{Path: path, Type: v.Type().String(), Value: "<nil>"})
+			return
+		}
+		collect(v.Elem(), opts, path, entries)
+		return
+	}
+
+	switch {
+	case isScalarVal(v) || isTextType(v.Type()) || isOpaque(v.Type()):
+		*entries = append(*entries, Entry{Path: path, Type: v.Type().String(), Value: show(v)})
+	case v.Kind() == reflect.Struct:
+		for _, child := range makeTree(v).Children {
+			collect(child.Value, inherit(opts, child.Options), joinPath(path, child.FieldName), entries)
+		}
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Array:
+		if v.Len() == 0 {
+			*entries = append(*entries, Entry{Path: path, Type: v.Type().String(), Value: "[]"})
+		}
+		for i := 0; i < v.Len(); i++ {
+			collect(v.Index(i), inherit(opts, fieldOptions{}), fmt.Sprintf("%s[%d]", path, i), entries)
+		}
+	case v.Kind() == reflect.Map:
+		if v.Len() == 0 {
+			*entries = append(*entries, Entry{Path: path, Type: v.Type().String(), Value: "{}"})
+		}
+		for _, key := range sortedKeys(v) {
+			collect(v.MapIndex(key), inherit(opts, fieldOptions{}), fmt.Sprintf("%s[%s]", path, formatValue(key)), entries)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package structconfig
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type workers struct {
+	MinWorkers int
+	MaxWorkers int
+	Name       string
+}
+
+func (w workers) Validate() error {
+	if w.MinWorkers > w.MaxWorkers {
+		return fmt.Errorf("MinWorkers must not be more than MaxWorkers")
+	}
+	return nil
+}
+
+func TestSetValidates(t *testing.T) {
+	tests := []struct {
+		name  string
+		start workers
+		path  string
+		value string
+		want  workers
+		fails bool
+	}{
+		{"valid", workers{1, 5, ""}, "MinWorkers", "3", workers{3, 5, ""}, false},
+		{"breaks validate", workers{1, 5, ""}, "MinWorkers", "10", workers{1, 5, ""}, true},
+		{"fixes validate", workers{10, 5, ""}, "MaxWorkers", "20", workers{10, 20, ""}, false},
+		{"already broken", workers{10, 5, ""}, "Name", "x", workers{10, 5, "x"}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := test.start
+			err := Set(&cfg, test.path, test.value)
+			if (err != nil) != test.fails {
+				t.Fatalf("got error %v, want failure %v", err, test.fails)
+			}
+			if cfg != test.want {
+				t.Fatalf("got %+v, want %+v", cfg, test.want)
+			}
+		})
+	}
+}
+
+func TestSetRunsAddedValidators(t *testing.T) {
+	editor := NewConfig[workers]()
+	editor.AddValidator("Name", func(value any) error {
+		if value.(string) == "root" {
+			return fmt.Errorf("reserved name")
+		}
+		return nil
+	})
+
+	cfg := workers{1, 5, "a"}
+	if err := editor.Set(&cfg, "Name", "root"); err == nil {
+		t.Fatal("expected the validator to reject the value")
+	}
+	if cfg.Name != "a" {
+		t.Fatalf("value changed to %q after being rejected", cfg.Name)
+	}
+}
+
+type pathServer struct {
+	Host string
+	Port int
+}
+
+type pathConfig struct {
+	Name    string
+	Server  pathServer
+	Backup  *pathServer
+	Hosts   []string
+	Limits  map[string]int
+	Timeout time.Duration
+	Token   string `sc:"secret"`
+	Fixed   int    `sc:"readonly"`
+}
+
+func TestGetSet(t *testing.T) {
+	tests := []struct {
+		path  string
+		value string
+		want  string
+		fails bool
+	}{
+		{"Name", "  spaced  ", "  spaced  ", false},
+		{"name", "x", "x", false},
+		{"Server.Port", " 8080 ", "8080", false},
+		{"Server.Port", "abc", "", true},
+		{"Server.Port", "99999999999999999999", "", true},
+		{"Backup.Host", "b", "b", false},
+		{"Hosts[0]", "a", "a", false},
+		{"Hosts[1]", "b", "", true},
+		{"Limits[cpu]", "2", "2", false},
+		{"Timeout", "1m30s", "1m30s", false},
+		{"Timeout", "soon", "", true},
+		{"Fixed", "1", "", true},
+		{"Missing", "1", "", true},
+		{"Server[0]", "1", "", true},
+		{"Hosts[", "1", "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path+"="+test.value, func(t *testing.T) {
+			cfg := pathConfig{}
+			err := Set(&cfg, test.path, test.value)
+			if (err != nil) != test.fails {
+				t.Fatalf("got error %v, want failure %v", err, test.fails)
+			}
+			if test.fails {
+				if !reflect.DeepEqual(cfg, pathConfig{}) {
+					t.Fatalf("config changed after a failed set: %+v", cfg)
+				}
+				return
+			}
+
+			got, err := Get(&cfg, test.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Fatalf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestListMasksSecrets(t *testing.T) {
+	cfg := pathConfig{Token: "hunter2", Hosts: []string{"a"}}
+
+	for _, entry := range List(&cfg) {
+		if entry.Path == "Token" && entry.Value == "hunter2" {
+			t.Fatal("secret value listed in plain text")
+		}
+		if entry.Path == "Hosts[0]" && entry.Value != "a" {
+			t.Fatalf("got %q for Hosts[0]", entry.Value)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+//go:build !windows
+
+package structconfig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lspaccatrosi16/go-cli-tools/termtest"
+)
+
+type runConfig struct {
+	Name string
+	Port int
+}
+
+func TestRun(t *testing.T) {
+	cfg := runConfig{Name: "alice", Port: 80}
+
+	s := termtest.New(t, termtest.Options{Height: 40})
+
+	var changed bool
+	var err error
+	s.Run(func() {
+		changed, err = NewConfig[runConfig]().Run(&cfg)
+	})
+
+	s.Expect("Select the command to execute")
+	s.Type("Name")
+	s.Press("enter")
+	s.Expect("New value?")
+	s.Type("bob\r")
+
+	s.Expect("1 changes")
+	s.Type("Review")
+	s.Press("enter")
+	s.Expect("Name : alice → bob")
+	s.Press("enter")
+	s.Wait()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed {
+		t.Error("Run reported no change")
+	}
+	if want := (runConfig{Name: "bob", Port: 80}); cfg != want {
+		t.Errorf("got %+v, want %+v", cfg, want)
+	}
+}
+
+func TestRunDiscard(t *testing.T) {
+	cfg := runConfig{Name: "alice", Port: 80}
+
+	s := termtest.New(t, termtest.Options{Height: 40})
+
+	var changed bool
+	var err error
+	s.Run(func() {
+		changed, err = NewConfig[runConfig]().Run(&cfg)
+	})
+
+	s.Expect("Select the command to execute")
+	s.Type("Port")
+	s.Press("enter")
+	s.Expect("New value [80]?")
+	s.Type("8080\r")
+
+	s.Expect("1 changes")
+	s.Type("Back")
+	s.Press("enter")
+	s.Expect("Discard unsaved changes")
+	s.Press("enter")
+	s.Wait()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed || cfg.Port != 80 {
+		t.Errorf("changes were kept: %v %+v", changed, cfg)
+	}
+}
+
+type secretConfig struct {
+	Pin int `sc:"secret"`
+}
+
+func TestRunSecretNumber(t *testing.T) {
+	cfg := secretConfig{Pin: 1234}
+
+	s := termtest.New(t, termtest.Options{Height: 40})
+
+	var err error
+	s.Run(func() {
+		_, err = NewConfig[secretConfig]().Run(&cfg)
+	})
+
+	s.Expect("Select the command to execute")
+	s.Type("Pin")
+	s.Press("enter")
+	s.Expect("New value?")
+	s.Type("x\r")
+	s.Expect("is not")
+	s.Type("5678\r")
+
+	s.Expect("1 changes")
+	s.Type("Review")
+	s.Press("enter")
+	s.Expect("Pin : ******** → ********")
+	s.Press("enter")
+	s.Wait()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Pin != 5678 {
+		t.Errorf("got %d, want 5678", cfg.Pin)
+	}
+	for _, value := range []string{"1234", "5678"} {
+		if strings.Contains(s.Output(), value) {
+			t.Errorf("%s was written to the terminal", value)
+		}
+	}
+}
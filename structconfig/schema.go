@@ -0,0 +1,240 @@
+package structconfig
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/lspaccatrosi16/go-cli-tools/config"
+)
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// JSONSchema describes the JSON file that T is stored in, using the sc tags
+// for titles, descriptions and constraints and defaults for default values.
+// Pass it to config.ReadConfigFileWithConfig to check files as they are read.
+func JSONSchema[T any](defaults T) *config.Schema {
+	t := reflect.TypeOf(&defaults).Elem()
+
+	schema := typeSchema(t, fieldOptions{}, map[reflect.Type]bool{})
+	schema.Schema = "https://json-schema.org/draft/2020-12/schema"
+	if schema.Title == "" {
+		schema.Title = t.Name()
+	}
+
+	data, err := json.Marshal(defaults)
+	if err == nil {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+
+		var doc any
+		if decoder.Decode(&doc) == nil {
+			applyDefaults(schema, doc)
+		}
+	}
+
+	return schema
+}
+
+func typeSchema(t reflect.Type, opts fieldOptions, seen map[reflect.Type]bool) *config.Schema {
+	if t.Kind() == reflect.Pointer && !isTextType(t) {
+		inner := typeSchema(t.Elem(), opts, seen)
+		if opts.Required {
+			return inner
+		}
+
+		schema := &config.Schema{
+			Title:       inner.Title,
+			Description: inner.Description,
+			ReadOnly:    inner.ReadOnly,
+			WriteOnly:   inner.WriteOnly,
+			AnyOf:       []*config.Schema{inner, {Type: "null"}},
+		}
+		inner.Title, inner.Description, inner.ReadOnly, inner.WriteOnly = "", "", false, false
+		return schema
+	}
+
+	schema := baseSchema(t, seen)
+	schema.Title = opts.Label
+	schema.Description = opts.Help
+	schema.ReadOnly = opts.ReadOnly
+	schema.WriteOnly = opts.Secret
+
+	var minimum, maximum *int
+	if opts.Min != nil {
+		n := int(*opts.Min)
+		minimum = &n
+	}
+	if opts.Max != nil {
+		n := int(*opts.Max)
+		maximum = &n
+	}
+	if opts.Required && minimum == nil {
+		n := 1
+		minimum = &n
+	}
+
+	switch schema.Type {
+	case "string":
+		schema.MinLength, schema.MaxLength = minimum, maximum
+		if opts.Regex != nil {
+			schema.Pattern = opts.Regex.String()
+		}
+	case "array":
+		if t.Kind() != reflect.Array {
+			schema.MinItems, schema.MaxItems = minimum, maximum
+		}
+	case "object":
+		if t.Kind() == reflect.Map {
+			schema.MinProperties, schema.MaxProperties = minimum, maximum
+		}
+	case "integer", "number":
+		schema.Minimum, schema.Maximum = opts.Min, opts.Max
+	}
+
+	if len(opts.OneOf) > 0 {
+		schema.Enum = []any{}
+		for _, option := range opts.OneOf {
+			value, err := parseValue(t, option)
+			if err != nil {
+				continue
+			}
+			schema.Enum = append(schema.Enum, value.Interface())
+		}
+	} else if options := enumOptions(reflect.New(t).Elem()); len(options) > 0 && schema.Type == "string" {
+		schema.Enum = []any{}
+		for _, option := range options {
+			schema.Enum = append(schema.Enum, option)
+		}
+	}
+
+	return schema
+}
+
+// baseSchema describes how encoding/json writes a value of type t.
+func baseSchema(t reflect.Type, seen map[reflect.Type]bool) *config.Schema {
+	switch {
+	case t == durationType:
+		return &config.Schema{Type: "integer", Format: "duration-nanoseconds"}
+	case t == timeType:
+		return &config.Schema{Type: "string", Format: "date-time"}
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return &config.Schema{}
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return &config.Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &config.Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &config.Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &config.Schema{Type: "number"}
+	case reflect.String:
+		return &config.Schema{Type: "string"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &config.Schema{Type: "string", Format: "base64"}
+		}
+		return &config.Schema{Type: "array", Items: typeSchema(t.Elem(), fieldOptions{}, seen)}
+	case reflect.Array:
+		size := t.Len()
+		return &config.Schema{Type: "array", Items: typeSchema(t.Elem(), fieldOptions{}, seen), MinItems: &size, MaxItems: &size}
+	case reflect.Map:
+		schema := &config.Schema{Type: "object", AdditionalProperties: typeSchema(t.Elem(), fieldOptions{}, seen)}
+		switch {
+		case t.Key().Kind() == reflect.String:
+		case isScalar(t.Key().Kind()) && t.Key().Kind() != reflect.Bool:
+			schema.PropertyNames = &config.Schema{Type: "string", Pattern: `^-?[0-9]+$`}
+		}
+		return schema
+	case reflect.Struct:
+		if seen[t] {
+			// recursive types are left open rather than expanded forever
+			return &config.Schema{Type: "object"}
+		}
+		seen[t] = true
+		defer delete(seen, t)
+
+		schema := &config.Schema{Type: "object", Properties: map[string]*config.Schema{}}
+		addFields(schema, t, seen)
+		return schema
+	default:
+		return &config.Schema{}
+	}
+}
+
+// addFields adds the fields of t as encoding/json names them, flattening
+// embedded structs the same way it does.
+func addFields(schema *config.Schema, t reflect.Type, seen map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" && options == "" {
+			continue
+		}
+
+		embedded := field.Type
+		if embedded.Kind() == reflect.Pointer {
+			embedded = embedded.Elem()
+		}
+		if field.Anonymous && name == "" && embedded.Kind() == reflect.Struct && !isTextType(embedded) {
+			addFields(schema, embedded, seen)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		opts := parseTag(field.Tag.Get("sc"))
+		if opts.Hidden {
+			// hidden fields are still written to the file
+			opts = fieldOptions{}
+		}
+
+		schema.Properties[name] = typeSchema(field.Type, opts, seen)
+		if opts.Required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// applyDefaults copies the values in doc, decoded from the defaults, onto the
+// matching properties. Secret values are left out.
+func applyDefaults(schema *config.Schema, doc any) {
+	values, ok := doc.(map[string]any)
+	if !ok {
+		return
+	}
+
+	for name, prop := range schema.Properties {
+		value, ok := values[name]
+		if !ok || value == nil || prop.WriteOnly {
+			continue
+		}
+
+		inner := prop
+		if len(prop.AnyOf) > 0 {
+			inner = prop.AnyOf[0]
+		}
+
+		if len(inner.Properties) > 0 {
+			applyDefaults(inner, value)
+			continue
+		}
+
+		prop.Default = value
+	}
+}
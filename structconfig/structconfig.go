@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"fmt"
 	"reflect"
-	"strconv"
 	"strings"
 
 	"github.com/lspaccatrosi16/go-cli-tools/command"
@@ -12,8 +11,51 @@ import (
 )
 
 type ConfigOutput[T any] struct {
-	exec *func() error
-	tree *node
+	exec       *func() error
+	tree       *node
+	defaults   *T
+	validators []fieldValidator
+}
+
+type fieldValidator struct {
+	path     string
+	segments []segment
+	validate func(value any) error
+}
+
+// AddValidator registers a check for the value at path, e.g. "Server.Port".
+// Like Validate methods it runs after every edit and before saving.
+func (c *ConfigOutput[T]) AddValidator(path string, validate func(value any) error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		panic(err)
+	}
+	c.validators = append(c.validators, fieldValidator{path: path, segments: segments, validate: validate})
+}
+
+// problems returns everything wrong with v: sc tag constraints, Validate
+// methods and registered validators.
+func (c *ConfigOutput[T]) problems(v reflect.Value) []error {
+	errs := validateAll(v, fieldOptions{}, "")
+
+	for _, validator := range c.validators {
+		value, _, err := lookup(v, fieldOptions{}, validator.segments)
+		if err != nil {
+			// values that are not set, such as nil pointers, are not checked
+			continue
+		}
+		if err := validator.validate(value.Interface()); err != nil {
+			errs = append(errs, pathError(validator.path, err))
+		}
+	}
+
+	return errs
+}
+
+// SetDefaults lets the editor show where values differ from defaults and
+// reset them.
+func (c *ConfigOutput[T]) SetDefaults(defaults T) {
+	c.defaults = &defaults
 }
 
 func (c *ConfigOutput[T]) Analyze(s *T) {
@@ -22,6 +64,9 @@ func (c *ConfigOutput[T]) Analyze(s *T) {
 	}
 
 	tree := makeTree(reflect.ValueOf(s))
+	if c.defaults != nil {
+		attachDefaults(tree, reflect.ValueOf(c.defaults))
+	}
 	c.tree = tree
 }
 
@@ -46,42 +91,195 @@ func (c *ConfigOutput[T]) Debug() {
 	}
 }
 
-func (c *ConfigOutput[T]) Run(s *T) error {
+// Run edits a copy of s and only writes it back when the user saves from the
+// "Review & save" entry. It reports whether s was changed.
+func (c *ConfigOutput[T]) Run(s *T) (bool, error) {
 	if s == nil {
 		panic("input is nil pointer")
 	}
 
-	c.Analyze(s)
-	c.Traverse()
-	return c.Execute()
+	original := reflect.ValueOf(s).Elem()
+	working := deepCopy(original).Interface().(T)
+	edited := reflect.ValueOf(&working).Elem()
+
+	c.Analyze(&working)
+	c.tree.Check = func() []error {
+		return c.problems(edited)
+	}
+
+	for {
+		action := ""
+		manager := makeManager(c.tree)
+		changes := diffValues("", fieldOptions{}, original, edited)
+		problems := c.problems(edited)
+
+		summary := fmt.Sprintf("%d changes", len(changes))
+		if len(problems) > 0 {
+			summary += fmt.Sprintf(", %d problems", len(problems))
+		}
+
+		manager.Register("Review & save", summary, func() error {
+			var err error
+			action, err = reviewChanges(changes, problems)
+			return err
+		})
+
+		if manager.Tui() {
+			if len(diffValues("", fieldOptions{}, original, edited)) == 0 {
+				return false, nil
+			}
+
+			discard, err := input.GetConfirmSelection("Discard unsaved changes")
+			if err != nil {
+				return false, err
+			}
+			if discard {
+				return false, nil
+			}
+			continue
+		}
+
+		switch action {
+		case "save":
+			if len(changes) == 0 {
+				return false, nil
+			}
+			original.Set(edited)
+			return true, nil
+		case "discard":
+			return false, nil
+		}
+	}
 }
 
 type node struct {
 	Children   []*node
 	Value      reflect.Value
+	Default    reflect.Value
 	FieldName  string
 	TypeString string
 	Parent     *node
+	Options    fieldOptions
+	// Pointer is the outermost pointer makeTree stepped through to reach
+	// Value, so that a reset can set it back to nil
+	Pointer reflect.Value
+	// Check is set on the root while editing and reports what is wrong with
+	// the whole struct
+	Check func() []error
 }
 
+// report prints the problems found by the root's Check after an edit. The
+// edit is kept so the user can fix it or something it conflicts with.
+func (n *node) report() {
+	root := n
+	for root.Parent != nil {
+		root = root.Parent
+	}
+	if root.Check == nil {
+		return
+	}
+	for _, err := range root.Check() {
+		fmt.Printf("ERROR: %s\n", err.Error())
+	}
+}
+
+func (n *node) label() string {
+	if n.Options.Label != "" {
+		return n.Options.Label
+	}
+	return n.FieldName
+}
+
+func (n *node) description() string {
+	value := n.show(n.Value)
+	if n.differs() {
+		return fmt.Sprintf("%s (default %s)", value, n.show(n.Default))
+	}
+	return value
+}
+
+func (n *node) show(v reflect.Value) string {
+	if n.Options.Secret {
+		return "********"
+	}
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return "<nil>"
+	}
+	return formatValue(v)
+}
+
+// differs reports whether a leaf has a default that its value no longer
+// matches.
+func (n *node) differs() bool {
+	if !n.Default.IsValid() || len(n.Children) > 0 {
+		return false
+	}
+	// only a nil default is left behind the pointer that was stepped through
+	if n.Default.Type() != n.Value.Type() {
+		return true
+	}
+	return len(diffValues("", n.Options, n.Default, n.Value)) > 0
+}
+
+// reset sets a leaf back to its default. A nil default clears the pointer
+// the value was reached through, and the node then holds that pointer as
+// makeTree would have built it.
+func (n *node) reset() {
+	if n.Default.Type() != n.Value.Type() {
+		n.Pointer.Set(reflect.Zero(n.Pointer.Type()))
+		n.Value = n.Pointer
+		n.Pointer = reflect.Value{}
+		return
+	}
+	n.Value.Set(deepCopy(n.Default))
+}
+
+// String renders the tree with one field per line, indented by depth.
 func (n *node) String() string {
 	buf := bytes.NewBuffer(nil)
+	n.write(buf, 0)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func (n *node) write(buf *bytes.Buffer, depth int) {
+	indent := strings.Repeat("  ", depth)
 
 	if len(n.Children) > 0 {
-		fmt.Fprintf(buf, "STRUCT (%d):\n", len(n.Children))
-		for _, n := range n.Children {
-			s := n.String()
-			lines := strings.Split(s, "\n")
-			for _, line := range lines {
-				fmt.Fprintf(buf, "  %s\n", line)
+		if n.FieldName != "" {
+			fmt.Fprintf(buf, "%s%s\n", indent, n.label())
+			depth++
+		}
+		for _, child := range n.Children {
+			child.write(buf, depth)
+		}
+		return
+	}
 
+	fmt.Fprintf(buf, "%s%s (%s) : %s\n", indent, n.label(), n.TypeString, n.description())
+}
+
+// attachDefaults gives every node in the tree the matching part of d,
+// stepping through the same pointers as makeTree. A nil default for a leaf
+// that is set is kept as it is, so that it shows up as a change.
+func attachDefaults(n *node, d reflect.Value) {
+	for d.IsValid() && d.Kind() == reflect.Pointer && d.Type() != n.Value.Type() {
+		if d.IsNil() {
+			if len(n.Children) > 0 {
+				d = reflect.Value{}
 			}
+			break
 		}
-		fmt.Fprintln(buf, "END")
-	} else {
-		fmt.Fprintf(buf, "%s: %s (%v)", n.FieldName, n.TypeString, n.Value.Interface())
+		d = d.Elem()
+	}
+	n.Default = d
+
+	for _, child := range n.Children {
+		childDefault := reflect.Value{}
+		if d.IsValid() && d.Kind() == reflect.Struct {
+			childDefault = d.FieldByName(child.FieldName)
+		}
+		attachDefaults(child, childDefault)
 	}
-	return buf.String()
 }
 
 func NewConfig[T any]() *ConfigOutput[T] {
@@ -89,13 +287,86 @@ func NewConfig[T any]() *ConfigOutput[T] {
 }
 
 func traverseTree(n *node) func() error {
+	if n.Options.ReadOnly {
+		return func() error {
+			return fmt.Errorf("%s is read only", n.label())
+		}
+	}
+
+	if isOpaque(n.Value.Type()) {
+		return func() error {
+			return fmt.Errorf("%s cannot be edited here, change it in the config file", n.label())
+		}
+	}
+
+	if len(n.Children) > 0 {
+		return editorFor(n)
+	}
+
+	edit := checked(n, editorFor(n))
+	if !n.Default.IsValid() {
+		return edit
+	}
+
+	return func() error {
+		if !n.differs() {
+			return edit()
+		}
+
+		items := []input.SelectOption{
+			{Name: "Edit value", Value: "edit"},
+			{Name: fmt.Sprintf("Reset to default (%s)", n.show(n.Default)), Value: "reset"},
+		}
+		selected, err := input.GetSelection(n.label(), items)
+		if err != nil {
+			return err
+		}
+		if selected == "reset" {
+			n.reset()
+			n.report()
+			return nil
+		}
+		return edit()
+	}
+}
+
+func checked(n *node, edit func() error) func() error {
+	return func() error {
+		if err := edit(); err != nil {
+			return err
+		}
+		n.report()
+		return nil
+	}
+}
+
+func editorFor(n *node) func() error {
 	if len(n.Children) > 0 {
 		return makeList(n)
-	} else {
+	}
+
+	if isTextType(n.Value.Type()) {
+		return updateVal(n)
+	}
+
+	switch n.Value.Kind() {
+	case reflect.Pointer:
+		return editPointer(n)
+	case reflect.Slice, reflect.Array:
+		return editSlice(n)
+	case reflect.Map:
+		return editMap(n)
+	default:
 		return updateVal(n)
 	}
 }
 
+func editValue(name string, v reflect.Value) error {
+	n := makeTree(v)
+	n.FieldName = name
+	return traverseTree(n)()
+}
+
 func makeList(n *node) func() error {
 	manager := makeManager(n)
 
@@ -118,7 +389,7 @@ func makeManager(n *node) *command.Manager {
 	for i := 0; i < len(n.Children); i++ {
 		child := n.Children[i]
 		f := traverseTree(child)
-		manager.Register(child.FieldName, fmt.Sprint(child.Value.Interface()), f)
+		manager.RegisterWithHelp(child.label(), child.description(), child.Options.Help, f)
 	}
 
 	return &manager
@@ -126,38 +397,78 @@ func makeManager(n *node) *command.Manager {
 
 func updateVal(n *node) func() error {
 	return func() error {
-		var err error
-		var vInt int
-		var vFloat float64
-		var vBool bool
-		var vStr string
-
-	inputVal:
-		vStr = input.GetInput("New value")
-		switch n.Value.Kind() {
-		case reflect.Int:
-			vInt, err = strconv.Atoi(vStr)
+		t := n.Value.Type()
+
+		options := n.Options.OneOf
+		if len(options) == 0 {
+			options = enumOptions(n.Value)
+		}
+		if len(options) > 0 {
+			return selectVal(n, options)
+		}
+
+		switch {
+		case t.Kind() == reflect.Bool:
+			vBool, err := input.GetConfirmSelection(n.label())
 			if err != nil {
-				fmt.Println("could not parse input. try again")
-				goto inputVal
+				return err
 			}
-			n.Value.Set(reflect.ValueOf(vInt))
-		case reflect.Float64:
-			vFloat, err = strconv.ParseFloat(vStr, 64)
-			if err != nil {
-				fmt.Println("could not parse input. try again")
-				goto inputVal
+			if err := n.Options.validate(reflect.ValueOf(vBool)); err != nil {
+				return err
 			}
-			n.Value.Set(reflect.ValueOf(vFloat))
-		case reflect.Bool:
-			vBool, err = strconv.ParseBool(vStr)
+			n.Value.SetBool(vBool)
+		case t.Kind() == reflect.String && !isTextType(t):
+			validator := func(str string) error {
+				return n.Options.validate(reflect.ValueOf(str))
+			}
+
+			var vStr string
+			if n.Options.Secret {
+				var err error
+				vStr, err = input.GetSecretInput("New value", input.SecretConfig{Mask: '*', Validator: validator})
+				if err != nil {
+					return err
+				}
+			} else {
+				var err error
+				vStr, err = input.GetValidatedInputErr("New value", validator)
+				if err != nil {
+					return err
+				}
+			}
+			n.Value.SetString(vStr)
+		case isScalar(t.Kind()) || isTextType(t):
+			parse := func(str string) (reflect.Value, error) {
+				v, err := parseValue(t, str)
+				if err != nil {
+					return v, err
+				}
+				return v, n.Options.validate(v)
+			}
+
+			// the parsed prompt shows the current value and echoes the new one
+			if n.Options.Secret {
+				vStr, err := input.GetSecretInput("New value", input.SecretConfig{Mask: '*', Validator: func(str string) error {
+					_, err := parse(str)
+					return err
+				}})
+				if err != nil {
+					return err
+				}
+				v, err := parse(vStr)
+				if err != nil {
+					return err
+				}
+				n.Value.Set(v)
+				return nil
+			}
+
+			current := n.Value
+			v, err := input.GetParsedInput("New value", &current, formatValue, parse)
 			if err != nil {
-				fmt.Println("could not parse input. try again")
-				goto inputVal
+				return err
 			}
-			n.Value.Set(reflect.ValueOf(vBool))
-		case reflect.String:
-			n.Value.Set(reflect.ValueOf(vStr))
+			n.Value.Set(v)
 		default:
 			return fmt.Errorf("invalid type: %s", n.Value.Kind())
 		}
@@ -165,25 +476,64 @@ func updateVal(n *node) func() error {
 	}
 }
 
+func selectVal(n *node, options []string) error {
+	items := []input.SelectOption{}
+	for _, option := range options {
+		items = append(items, input.SelectOption{Name: option, Value: option})
+	}
+
+	selected, _, err := input.GetSelectionWithConfig("New value", items, input.SelectConfig{Default: formatValue(n.Value)})
+	if err != nil {
+		return err
+	}
+
+	v, err := parseValue(n.Value.Type(), selected)
+	if err != nil {
+		return err
+	}
+	if err := n.Options.validate(v); err != nil {
+		return err
+	}
+
+	n.Value.Set(v)
+	return nil
+}
+
 func makeTree(v reflect.Value) *node {
 	children := []*node{}
 	if v.Kind() == reflect.Pointer {
-		return makeTree(v.Elem())
+		if v.IsNil() {
+			return &node{Value: v}
+		}
+		n := makeTree(v.Elem())
+		n.Pointer = v
+		return n
+	}
+
+	if isScalarVal(v) || isTextType(v.Type()) || isOpaque(v.Type()) {
+		return &node{Value: v}
 	}
 
-	if isScalarVal(v) {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
 		return &node{Value: v}
 	}
 
 	if v.Kind() == reflect.Struct {
-		parent := node{}
+		parent := node{Value: v}
 		numField := v.NumField()
 		for i := 0; i < numField; i++ {
+			t := v.Type().Field(i)
+			options := parseTag(t.Tag.Get("sc"))
+			if !t.IsExported() || options.Hidden {
+				continue
+			}
+
 			f := v.Field(i)
 			n := makeTree(f)
-			t := v.Type().Field(i)
+			n.Options = options
 			n.FieldName = t.Name
-			n.TypeString = t.Type.Name()
+			n.TypeString = t.Type.String()
 			n.Parent = &parent
 			children = append(children, n)
 		}
@@ -201,7 +551,9 @@ func isScalarVal(v reflect.Value) bool {
 
 func isScalar(k reflect.Kind) bool {
 	switch k {
-	case reflect.Int, reflect.Float64, reflect.String, reflect.Bool:
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.String, reflect.Bool:
 		return true
 	default:
 		return false
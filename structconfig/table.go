@@ -0,0 +1,65 @@
+package structconfig
+
+import (
+	"github.com/lspaccatrosi16/go-cli-tools/output"
+)
+
+// Table lists every value in s with its path and type. When defaults is not
+// nil it also shows the default value and marks values that differ from it
+// with a *. Secret values are masked.
+func Table[T any](s *T, defaults *T) *output.Table {
+	current := List(s)
+
+	if defaults == nil {
+		table := output.NewTable(output.TableConfig{}, "Path", "Type", "Value")
+		for _, entry := range current {
+			table.AddRow(entry.Path, entry.Type, entry.Value)
+		}
+		return table
+	}
+
+	table := output.NewTable(output.TableConfig{}, "", "Path", "Type", "Value", "Default")
+
+	defaultEntries := map[string]Entry{}
+	for _, entry := range List(defaults) {
+		defaultEntries[entry.Path] = entry
+	}
+
+	addRow := func(path string, typeName string, value string, def string) {
+		marker := ""
+		if value != def || differsAt(s, defaults, path) {
+			marker = "*"
+		}
+		table.AddRow(marker, path, typeName, value, def)
+	}
+
+	seen := map[string]bool{}
+	for _, entry := range current {
+		seen[entry.Path] = true
+		def, ok := defaultEntries[entry.Path]
+		if !ok {
+			def.Value = "<none>"
+		}
+		addRow(entry.Path, entry.Type, entry.Value, def.Value)
+	}
+
+	// values only present in the defaults, e.g. removed slice elements
+	for _, entry := range List(defaults) {
+		if !seen[entry.Path] {
+			addRow(entry.Path, entry.Type, "<none>", entry.Value)
+		}
+	}
+
+	return table
+}
+
+// differsAt compares the unmasked values at path, as secrets look the same
+// once masked.
+func differsAt[T any](s *T, defaults *T, path string) bool {
+	value, err := Get(s, path)
+	if err != nil {
+		return false
+	}
+	def, err := Get(defaults, path)
+	return err == nil && value != def
+}
@@ -0,0 +1,222 @@
+package structconfig
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/lspaccatrosi16/go-cli-tools/config"
+)
+
+// fieldOptions holds the settings read from an `sc` struct tag, e.g.
+//
+//	Port int `sc:"label=Server port,help=Port to listen on,min=1,max=65535"`
+//
+// Commas inside a value are escaped as `\,` and oneof values are separated by
+// `|`. The tag `sc:"-"` hides a field.
+type fieldOptions struct {
+	Label    string
+	Help     string
+	Hidden   bool
+	ReadOnly bool
+	Secret   bool
+	Required bool
+	Min      *float64
+	Max      *float64
+	Regex    *regexp.Regexp
+	OneOf    []string
+}
+
+func parseTag(tag string) fieldOptions {
+	opts := fieldOptions{}
+
+	if tag == "-" {
+		opts.Hidden = true
+		return opts
+	}
+
+	for _, part := range splitTag(tag) {
+		key, value, _ := strings.Cut(part, "=")
+		key = strings.TrimSpace(key)
+
+		switch key {
+		case "":
+		case "label":
+			opts.Label = value
+		case "help":
+			opts.Help = value
+		case "hidden":
+			opts.Hidden = true
+		case "readonly":
+			opts.ReadOnly = true
+		case "secret":
+			opts.Secret = true
+		case "required":
+			opts.Required = true
+		case "min", "max":
+			f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				panic(fmt.Errorf("invalid %s in sc tag: %q", key, value))
+			}
+			if key == "min" {
+				opts.Min = &f
+			} else {
+				opts.Max = &f
+			}
+		case "regex":
+			re, err := regexp.Compile(value)
+			if err != nil {
+				panic(fmt.Errorf("invalid regex in sc tag: %s", err))
+			}
+			opts.Regex = re
+		case "oneof":
+			opts.OneOf = strings.Split(value, "|")
+		default:
+			panic(fmt.Errorf("unknown option in sc tag: %s", key))
+		}
+	}
+
+	return opts
+}
+
+func splitTag(tag string) []string {
+	parts := []string{}
+	current := strings.Builder{}
+
+	for i := 0; i < len(tag); i++ {
+		switch {
+		case tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ',':
+			current.WriteByte(',')
+			i++
+		case tag[i] == ',':
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(tag[i])
+		}
+	}
+
+	return append(parts, current.String())
+}
+
+func (o fieldOptions) validate(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		unit := "elements"
+		if v.Kind() == reflect.String {
+			unit = "characters"
+		}
+
+		size := float64(v.Len())
+		if o.Required && size == 0 {
+			return fmt.Errorf("value is required")
+		}
+		if o.Min != nil && size < *o.Min {
+			return fmt.Errorf("value must have at least %v %s", *o.Min, unit)
+		}
+		if o.Max != nil && size > *o.Max {
+			return fmt.Errorf("value must have at most %v %s", *o.Max, unit)
+		}
+	case reflect.Pointer:
+		if o.Required && v.IsNil() {
+			return fmt.Errorf("value is required")
+		}
+	default:
+		if n, ok := numericValue(v); ok {
+			if o.Min != nil && n < *o.Min {
+				return fmt.Errorf("value must be at least %v", *o.Min)
+			}
+			if o.Max != nil && n > *o.Max {
+				return fmt.Errorf("value must be at most %v", *o.Max)
+			}
+		}
+	}
+
+	if !isScalarVal(v) && !isTextType(v.Type()) {
+		return nil
+	}
+
+	str := formatValue(v)
+
+	if o.Regex != nil && !o.Regex.MatchString(str) {
+		return fmt.Errorf("value must match %s", o.Regex.String())
+	}
+
+	if len(o.OneOf) > 0 {
+		for _, option := range o.OneOf {
+			if str == option {
+				return nil
+			}
+		}
+		return fmt.Errorf("value must be one of %s", strings.Join(o.OneOf, ", "))
+	}
+
+	return nil
+}
+
+func numericValue(v reflect.Value) (float64, bool) {
+	switch {
+	case v.CanInt():
+		return float64(v.Int()), true
+	case v.CanUint():
+		return float64(v.Uint()), true
+	case v.CanFloat():
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// validateAll checks every field of v against its tags and Validate methods,
+// so that values which were never edited are caught as well.
+func validateAll(v reflect.Value, opts fieldOptions, path string) []error {
+	if err := opts.validate(v); err != nil {
+		return []error{pathError(path, err)}
+	}
+
+	if v.Kind() == reflect.Pointer && !isTextType(v.Type()) {
+		if v.IsNil() {
+			return nil
+		}
+		return validateAll(v.Elem(), opts, path)
+	}
+
+	if options := enumOptions(v); len(options) > 0 && !contains(options, formatValue(v)) {
+		return []error{pathError(path, fmt.Errorf("value must be one of %s", strings.Join(options, ", ")))}
+	}
+
+	errs := []error{}
+
+	switch {
+	case isScalarVal(v) || isTextType(v.Type()) || isOpaque(v.Type()):
+	case v.Kind() == reflect.Struct:
+		for _, child := range makeTree(v).Children {
+			errs = append(errs, validateAll(child.Value, child.Options, joinPath(path, child.FieldName))...)
+		}
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			errs = append(errs, validateAll(v.Index(i), fieldOptions{}, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case v.Kind() == reflect.Map:
+		for _, key := range sortedKeys(v) {
+			errs = append(errs, validateAll(v.MapIndex(key), fieldOptions{}, fmt.Sprintf("%s[%s]", path, formatValue(key)))...)
+		}
+	}
+
+	if validator, ok := config.AsValidator(v); ok {
+		if err := validator.Validate(); err != nil {
+			errs = append(errs, pathError(path, err))
+		}
+	}
+
+	return errs
+}
+
+func pathError(path string, err error) error {
+	if path == "" {
+		return err
+	}
+	return fmt.Errorf("%s: %s", path, err.Error())
+}
@@ -0,0 +1,187 @@
+package structconfig
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/lspaccatrosi16/go-cli-tools/input"
+)
+
+type change struct {
+	Path string
+	Old  string
+	New  string
+}
+
+// deepCopy copies v so that editing the copy never writes through a shared
+// pointer, slice or map into the original. Unexported fields are copied
+// shallowly as they cannot be edited.
+func deepCopy(v reflect.Value) reflect.Value {
+	out := reflect.New(v.Type()).Elem()
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			p := reflect.New(v.Type().Elem())
+			p.Elem().Set(deepCopy(v.Elem()))
+			out.Set(p)
+		}
+	case reflect.Slice:
+		if !v.IsNil() {
+			s := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+			for i := 0; i < v.Len(); i++ {
+				s.Index(i).Set(deepCopy(v.Index(i)))
+			}
+			out.Set(s)
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(deepCopy(v.Index(i)))
+		}
+	case reflect.Map:
+		if !v.IsNil() {
+			m := reflect.MakeMapWithSize(v.Type(), v.Len())
+			iter := v.MapRange()
+			for iter.Next() {
+				m.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+			}
+			out.Set(m)
+		}
+	case reflect.Struct:
+		out.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				out.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+	default:
+		out.Set(v)
+	}
+
+	return out
+}
+
+func diffValues(path string, opts fieldOptions, a reflect.Value, b reflect.Value) []change {
+	show := func(v reflect.Value) string {
+		if opts.Secret {
+			return "********"
+		}
+		return formatValue(v)
+	}
+
+	// elements of a secret collection are secret too
+	inner := fieldOptions{Secret: opts.Secret}
+
+	switch {
+	case isScalarVal(a) || isTextType(a.Type()):
+		if formatValue(a) != formatValue(b) {
+			return []change{{Path: path, Old: show(a), New: show(b)}}
+		}
+	case a.Kind() == reflect.Pointer:
+		if a.IsNil() && b.IsNil() {
+			return nil
+		}
+		if a.IsNil() || b.IsNil() {
+			return []change{{Path: path, Old: showPointer(a, show), New: showPointer(b, show)}}
+		}
+		return diffValues(path, opts, a.Elem(), b.Elem())
+	case a.Kind() == reflect.Struct:
+		changes := []change{}
+		for i := 0; i < a.NumField(); i++ {
+			field := a.Type().Field(i)
+			fieldOpts := parseTag(field.Tag.Get("sc"))
+			if !field.IsExported() || fieldOpts.Hidden {
+				continue
+			}
+			changes = append(changes, diffValues(joinPath(path, field.Name), fieldOpts, a.Field(i), b.Field(i))...)
+		}
+		return changes
+	case a.Kind() == reflect.Slice || a.Kind() == reflect.Array:
+		changes := []change{}
+		for i := 0; i < max(a.Len(), b.Len()); i++ {
+			elemPath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= a.Len():
+				changes = append(changes, change{Path: elemPath, Old: "<none>", New: show(b.Index(i))})
+			case i >= b.Len():
+				changes = append(changes, change{Path: elemPath, Old: show(a.Index(i)), New: "<none>"})
+			default:
+				changes = append(changes, diffValues(elemPath, inner, a.Index(i), b.Index(i))...)
+			}
+		}
+		return changes
+	case a.Kind() == reflect.Map:
+		changes := []change{}
+		seen := map[string]bool{}
+		for _, key := range append(sortedKeys(a), sortedKeys(b)...) {
+			name := formatValue(key)
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			elemPath := fmt.Sprintf("%s[%s]", path, name)
+			old, updated := a.MapIndex(key), b.MapIndex(key)
+			switch {
+			case !old.IsValid():
+				changes = append(changes, change{Path: elemPath, Old: "<none>", New: show(updated)})
+			case !updated.IsValid():
+				changes = append(changes, change{Path: elemPath, Old: show(old), New: "<none>"})
+			default:
+				changes = append(changes, diffValues(elemPath, inner, old, updated)...)
+			}
+		}
+		return changes
+	}
+
+	return nil
+}
+
+func showPointer(v reflect.Value, show func(reflect.Value) string) string {
+	if v.IsNil() {
+		return "<nil>"
+	}
+	return show(v.Elem())
+}
+
+func joinPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// reviewChanges prints the pending changes and any problems with them and asks
+// what to do. It returns "save", "discard" or "edit". Saving is only offered
+// when there are no problems.
+func reviewChanges(changes []change, problems []error) (string, error) {
+	if len(changes) == 0 {
+		fmt.Println("No changes")
+	} else {
+		width := 0
+		for _, c := range changes {
+			width = max(width, len(c.Path))
+		}
+		for _, c := range changes {
+			fmt.Printf("  %-*s : %s → %s\n", width, c.Path, c.Old, c.New)
+		}
+	}
+
+	items := []input.SelectOption{}
+
+	if len(problems) > 0 {
+		fmt.Println("Problems:")
+		for _, err := range problems {
+			fmt.Printf("  %s\n", err.Error())
+		}
+	} else {
+		items = append(items, input.SelectOption{Name: "Save", Value: "save"})
+	}
+
+	items = append(items,
+		input.SelectOption{Name: "Discard", Value: "discard"},
+		input.SelectOption{Name: "Keep editing", Value: "edit"},
+	)
+
+	return input.GetSelection("What should happen to these changes", items)
+}
@@ -0,0 +1,162 @@
+package structconfig
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Enum is implemented by types that only accept a fixed set of values. Such
+// fields are edited by picking one of the options.
+type Enum interface {
+	Options() []string
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	enumType            = reflect.TypeOf((*Enum)(nil)).Elem()
+)
+
+// isTextType reports whether t is parsed from a single string by its own
+// methods rather than field by field or element by element.
+func isTextType(t reflect.Type) bool {
+	return t == durationType || t == timeType || reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+func enumOptions(v reflect.Value) []string {
+	if v.Type().Implements(enumType) {
+		return v.Interface().(Enum).Options()
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(enumType) {
+		return v.Addr().Interface().(Enum).Options()
+	}
+	return nil
+}
+
+// isOpaque reports whether values of t are shown but cannot be edited or set
+// by path, such as an any field holding arbitrary JSON.
+func isOpaque(t reflect.Type) bool {
+	if isTextType(t) {
+		return false
+	}
+
+	switch t.Kind() {
+	case reflect.Interface, reflect.Func, reflect.Chan, reflect.UnsafePointer, reflect.Complex64, reflect.Complex128:
+		return true
+	case reflect.Map:
+		return !isScalar(t.Key().Kind()) || isOpaque(t.Elem())
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return isOpaque(t.Elem())
+	}
+
+	return false
+}
+
+func formatValue(v reflect.Value) string {
+	switch {
+	case v.Type() == durationType:
+		return time.Duration(v.Int()).String()
+	case v.Type() == timeType:
+		return v.Interface().(time.Time).Format(time.RFC3339)
+	}
+
+	if marshaler, ok := v.Interface().(encoding.TextMarshaler); ok {
+		if text, err := marshaler.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+
+	// arbitrary values read as they would be written to the config file
+	if isOpaque(v.Type()) {
+		if data, err := json.Marshal(v.Interface()); err == nil {
+			return string(data)
+		}
+	}
+
+	return fmt.Sprint(v.Interface())
+}
+
+func parseValue(t reflect.Type, str string) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+	// strings are kept as typed, as in the editor
+	if t.Kind() != reflect.String {
+		str = strings.TrimSpace(str)
+	}
+
+	switch {
+	case t == durationType:
+		d, err := time.ParseDuration(str)
+		if err != nil {
+			return v, fmt.Errorf("%q is not a duration such as 1h30m", str)
+		}
+		v.SetInt(int64(d))
+		return v, nil
+	case t == timeType:
+		ts, err := time.Parse(time.RFC3339, str)
+		if err != nil {
+			return v, fmt.Errorf("%q is not a time such as %s", str, time.RFC3339)
+		}
+		v.Set(reflect.ValueOf(ts))
+		return v, nil
+	case reflect.PointerTo(t).Implements(textUnmarshalerType):
+		if err := v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(str)); err != nil {
+			return v, err
+		}
+		return v, nil
+	}
+
+	return parseScalar(t, str)
+}
+
+func parseScalar(t reflect.Type, str string) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+	if t.Kind() != reflect.String {
+		str = strings.TrimSpace(str)
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(str, 10, t.Bits())
+		if err != nil {
+			return v, numError(str, t, err)
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(str, 10, t.Bits())
+		if err != nil {
+			return v, numError(str, t, err)
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(str, t.Bits())
+		if err != nil {
+			return v, numError(str, t, err)
+		}
+		v.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(str)
+		if err != nil {
+			return v, fmt.Errorf("%q is not true or false", str)
+		}
+		v.SetBool(b)
+	case reflect.String:
+		v.SetString(str)
+	default:
+		return v, fmt.Errorf("invalid type: %s", t.Kind())
+	}
+
+	return v, nil
+}
+
+func numError(str string, t reflect.Type, err error) error {
+	if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+		return fmt.Errorf("%s is out of range for %s", str, t.Kind())
+	}
+	return fmt.Errorf("%q is not a valid %s", str, t.Kind())
+}
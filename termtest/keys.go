@@ -0,0 +1,37 @@
+package termtest
+
+import "strings"
+
+var keys = map[string]string{
+	"enter":     "\r",
+	"tab":       "\t",
+	"backspace": "\x7f",
+	"esc":       "\x1b",
+	"escape":    "\x1b",
+	"space":     " ",
+	"up":        "\x1b[A",
+	"down":      "\x1b[B",
+	"right":     "\x1b[C",
+	"left":      "\x1b[D",
+	"home":      "\x1b[H",
+	"end":       "\x1b[F",
+	"delete":    "\x1b[3~",
+	"pgup":      "\x1b[5~",
+	"pgdown":    "\x1b[6~",
+}
+
+// keySequence returns the bytes a terminal sends for a named key such as
+// "down", "enter" or "ctrl+c".
+func keySequence(name string) (string, bool) {
+	name = strings.ToLower(name)
+
+	if seq, ok := keys[name]; ok {
+		return seq, true
+	}
+
+	if letter, ok := strings.CutPrefix(name, "ctrl+"); ok && len(letter) == 1 && letter[0] >= 'a' && letter[0] <= 'z' {
+		return string(rune(letter[0] - 'a' + 1)), true
+	}
+
+	return "", false
+}
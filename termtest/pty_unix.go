@@ -0,0 +1,108 @@
+//go:build !windows
+
+package termtest
+
+import (
+	"os"
+
+	"github.com/creack/pty"
+	"golang.org/x/sys/unix"
+)
+
+type terminal struct {
+	master   *os.File
+	slave    *os.File
+	savedIn  int
+	savedOut int
+}
+
+func openTerminal(width, height int) (*terminal, error) {
+	ptmx, slave, err := pty.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	// pty.Open leaves the master in blocking mode, which would stop Close from
+	// interrupting a pending Read. Reopen it through the runtime poller.
+	fd, err := unix.Dup(int(ptmx.Fd()))
+	ptmx.Close()
+	if err != nil {
+		slave.Close()
+		return nil, err
+	}
+	if err := unix.SetNonblock(fd, true); err != nil {
+		unix.Close(fd)
+		slave.Close()
+		return nil, err
+	}
+
+	t := &terminal{
+		master:   os.NewFile(uintptr(fd), "/dev/ptmx"),
+		slave:    slave,
+		savedIn:  -1,
+		savedOut: -1,
+	}
+
+	if err := pty.Setsize(slave, &pty.Winsize{Cols: uint16(width), Rows: uint16(height)}); err != nil {
+		t.close()
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// attach puts the terminal behind stdin and stdout of the whole process.
+func (t *terminal) attach() error {
+	var err error
+
+	if t.savedIn, err = unix.Dup(0); err != nil {
+		return err
+	}
+	if t.savedOut, err = unix.Dup(1); err != nil {
+		t.detach()
+		return err
+	}
+
+	for _, target := range []int{0, 1} {
+		if err := unix.Dup2(int(t.slave.Fd()), target); err != nil {
+			t.detach()
+			return err
+		}
+	}
+
+	return nil
+}
+
+// detach gives stdin and stdout back to whatever they were before attach.
+func (t *terminal) detach() error {
+	var firstErr error
+	keep := func(err error) {
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if t.savedIn != -1 {
+		keep(unix.Dup2(t.savedIn, 0))
+		keep(unix.Close(t.savedIn))
+		t.savedIn = -1
+	}
+	if t.savedOut != -1 {
+		keep(unix.Dup2(t.savedOut, 1))
+		keep(unix.Close(t.savedOut))
+		t.savedOut = -1
+	}
+
+	return firstErr
+}
+
+func (t *terminal) close() error {
+	err := t.detach()
+	if closeErr := t.slave.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := t.master.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
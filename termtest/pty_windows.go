@@ -0,0 +1,28 @@
+//go:build windows
+
+package termtest
+
+import (
+	"fmt"
+	"os"
+)
+
+type terminal struct {
+	master *os.File
+}
+
+func openTerminal(width, height int) (*terminal, error) {
+	return nil, fmt.Errorf("pseudo-terminals are not supported on windows")
+}
+
+func (t *terminal) attach() error {
+	return nil
+}
+
+func (t *terminal) detach() error {
+	return nil
+}
+
+func (t *terminal) close() error {
+	return nil
+}
@@ -0,0 +1,251 @@
+package termtest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type parseState int
+
+const (
+	stateGround parseState = iota
+	stateEscape
+	stateCSI
+	stateOSC
+)
+
+// screen is a minimal VT100 emulator covering the sequences written by
+// promptui, readline and the prompts in this module.
+type screen struct {
+	width, height int
+	cells         [][]rune
+	row, col      int
+	savedRow      int
+	savedCol      int
+
+	state   parseState
+	params  []byte
+	pending []byte
+}
+
+func newScreen(width, height int) *screen {
+	s := &screen{width: width, height: height}
+	s.cells = make([][]rune, height)
+	for i := range s.cells {
+		s.cells[i] = s.blankLine()
+	}
+	return s
+}
+
+func (s *screen) blankLine() []rune {
+	line := make([]rune, s.width)
+	for i := range line {
+		line[i] = ' '
+	}
+	return line
+}
+
+// feed interprets output written to the terminal and returns any reply the
+// terminal would send back, such as a cursor position report.
+func (s *screen) feed(data []byte) []byte {
+	reply := []byte{}
+
+	for _, b := range data {
+		switch s.state {
+		case stateGround:
+			s.ground(b)
+		case stateEscape:
+			s.escape(b)
+		case stateCSI:
+			if b >= 0x40 && b <= 0x7e {
+				reply = append(reply, s.csi(b)...)
+				s.state = stateGround
+			} else {
+				s.params = append(s.params, b)
+			}
+		case stateOSC:
+			if b == 7 {
+				s.state = stateGround
+			} else if b == 0x1b {
+				// ESC \ terminates the string, the backslash is dropped by escape
+				s.state = stateEscape
+			}
+		}
+	}
+
+	return reply
+}
+
+func (s *screen) ground(b byte) {
+	if len(s.pending) == 0 && b < 0x80 {
+		s.control(b)
+		return
+	}
+
+	s.pending = append(s.pending, b)
+	if !utf8.FullRune(s.pending) {
+		return
+	}
+
+	r, _ := utf8.DecodeRune(s.pending)
+	s.pending = s.pending[:0]
+	s.put(r)
+}
+
+func (s *screen) control(b byte) {
+	switch b {
+	case 0x1b:
+		s.state = stateEscape
+	case '\r':
+		s.col = 0
+	case '\n':
+		s.lineFeed()
+	case '\b':
+		if s.col > 0 {
+			s.col--
+		}
+	case '\t':
+		s.col = min((s.col/8+1)*8, s.width-1)
+	default:
+		if b >= 0x20 && b != 0x7f {
+			s.put(rune(b))
+		}
+	}
+}
+
+func (s *screen) escape(b byte) {
+	s.state = stateGround
+	switch b {
+	case '[':
+		s.params = s.params[:0]
+		s.state = stateCSI
+	case ']':
+		s.state = stateOSC
+	case '7':
+		s.savedRow, s.savedCol = s.row, s.col
+	case '8':
+		s.row, s.col = s.savedRow, s.savedCol
+	case 'c':
+		s.clear(0, 0, s.height-1, s.width-1)
+		s.row, s.col = 0, 0
+	}
+}
+
+func (s *screen) put(r rune) {
+	if s.col >= s.width {
+		s.col = 0
+		s.lineFeed()
+	}
+	s.cells[s.row][s.col] = r
+	s.col++
+}
+
+func (s *screen) lineFeed() {
+	if s.row < s.height-1 {
+		s.row++
+		return
+	}
+	s.cells = append(s.cells[1:], s.blankLine())
+}
+
+func (s *screen) csi(final byte) []byte {
+	raw := string(s.params)
+	if strings.HasPrefix(raw, "?") {
+		// private modes such as cursor visibility do not affect the contents
+		return nil
+	}
+
+	args := []int{}
+	for _, part := range strings.Split(raw, ";") {
+		n, _ := strconv.Atoi(part)
+		args = append(args, n)
+	}
+
+	arg := func(i, def int) int {
+		if i < len(args) && args[i] > 0 {
+			return args[i]
+		}
+		return def
+	}
+
+	switch final {
+	case 'A':
+		s.row = max(s.row-arg(0, 1), 0)
+	case 'B':
+		s.row = min(s.row+arg(0, 1), s.height-1)
+	case 'C':
+		s.col = min(s.col+arg(0, 1), s.width-1)
+	case 'D':
+		s.col = max(min(s.col, s.width-1)-arg(0, 1), 0)
+	case 'E':
+		s.row = min(s.row+arg(0, 1), s.height-1)
+		s.col = 0
+	case 'F':
+		s.row = max(s.row-arg(0, 1), 0)
+		s.col = 0
+	case 'G':
+		s.col = min(arg(0, 1)-1, s.width-1)
+	case 'H', 'f':
+		s.row = min(arg(0, 1)-1, s.height-1)
+		s.col = min(arg(1, 1)-1, s.width-1)
+	case 'J':
+		switch arg(0, 0) {
+		case 0:
+			s.clear(s.row, s.col, s.height-1, s.width-1)
+		case 1:
+			s.clear(0, 0, s.row, s.col)
+		default:
+			s.clear(0, 0, s.height-1, s.width-1)
+		}
+	case 'K':
+		switch arg(0, 0) {
+		case 0:
+			s.clear(s.row, s.col, s.row, s.width-1)
+		case 1:
+			s.clear(s.row, 0, s.row, s.col)
+		default:
+			s.clear(s.row, 0, s.row, s.width-1)
+		}
+	case 's':
+		s.savedRow, s.savedCol = s.row, s.col
+	case 'u':
+		s.row, s.col = s.savedRow, s.savedCol
+	case 'n':
+		if arg(0, 0) == 6 {
+			return []byte(fmt.Sprintf("\x1b[%d;%dR", s.row+1, min(s.col, s.width-1)+1))
+		}
+	}
+
+	return nil
+}
+
+// clear blanks every cell from (fromRow, fromCol) up to and including
+// (toRow, toCol) in reading order.
+func (s *screen) clear(fromRow, fromCol, toRow, toCol int) {
+	for r := fromRow; r <= toRow; r++ {
+		start, end := 0, s.width-1
+		if r == fromRow {
+			start = fromCol
+		}
+		if r == toRow {
+			end = min(toCol, s.width-1)
+		}
+		for c := start; c <= end; c++ {
+			s.cells[r][c] = ' '
+		}
+	}
+}
+
+func (s *screen) lines() []string {
+	lines := make([]string, s.height)
+	for i, line := range s.cells {
+		lines[i] = strings.TrimRight(string(line), " ")
+	}
+	return lines
+}
+
+func (s *screen) String() string {
+	return strings.TrimRight(strings.Join(s.lines(), "\n"), "\n")
+}
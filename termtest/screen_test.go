@@ -0,0 +1,73 @@
+package termtest
+
+import "testing"
+
+func TestScreenFeed(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"text", "hello\r\nworld", "hello\nworld"},
+		{"carriage return overwrites", "hello\rj", "jello"},
+		{"backspace", "ab\bc", "ac"},
+		{"utf8 split", "caf\xc3\xa9", "café"},
+		{"cursor up and clear line", "one\r\ntwo\x1b[1A\r\x1b[2Kthree", "three\ntwo"},
+		{"clear to end of line", "abcdef\x1b[3D\x1b[K", "abc"},
+		{"clear screen", "abc\r\ndef\x1b[2J\x1b[Hx", "x"},
+		{"cursor position", "\x1b[2;3Hx", "\n  x"},
+		{"save and restore", "a\x1b7bc\x1b8X", "aXc"},
+		{"private modes ignored", "\x1b[?25lhi\x1b[?25h", "hi"},
+		{"osc title dropped", "\x1b]0;title\x07hi", "hi"},
+		{"wraps long lines", "abcdefghij", "abcdefgh\nij"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newScreen(8, 4)
+			s.feed([]byte(test.input))
+			if got := s.String(); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestScreenScrolls(t *testing.T) {
+	s := newScreen(8, 2)
+	s.feed([]byte("one\r\ntwo\r\nthree"))
+	if got := s.String(); got != "two\nthree" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestScreenCursorReport(t *testing.T) {
+	s := newScreen(8, 4)
+	reply := s.feed([]byte("\r\nab\x1b[6n"))
+	if string(reply) != "\x1b[2;3R" {
+		t.Errorf("got reply %q", reply)
+	}
+}
+
+func TestKeySequence(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{"enter", "\r", true},
+		{"Down", "\x1b[B", true},
+		{"space", " ", true},
+		{"ctrl+c", "\x03", true},
+		{"ctrl+D", "\x04", true},
+		{"ctrl+1", "", false},
+		{"f13", "", false},
+	}
+
+	for _, test := range tests {
+		got, ok := keySequence(test.name)
+		if got != test.want || ok != test.ok {
+			t.Errorf("keySequence(%q) = %q, %v, want %q, %v", test.name, got, ok, test.want, test.ok)
+		}
+	}
+}
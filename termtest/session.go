@@ -0,0 +1,386 @@
+package termtest
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lspaccatrosi16/go-cli-tools/internal/tty"
+)
+
+// stdin and stdout belong to the whole process, so only one session can be
+// attached at a time.
+var sessionLock sync.Mutex
+
+type Options struct {
+	Width  int
+	Height int
+	// Timeout bounds every Expect and Wait call. Defaults to 5 seconds.
+	Timeout time.Duration
+	// KeyDelay is paused after each key so that escape sequences of separate
+	// keys are not read as one. Defaults to 20 milliseconds.
+	KeyDelay time.Duration
+}
+
+// Session runs interactive flows against a pseudo-terminal and records what
+// they draw on a virtual screen. While a flow is running, stdin and stdout of
+// the whole test process are connected to the terminal, so anything the test
+// itself logs before Wait returns ends up on the screen too.
+//
+//	s := termtest.New(t, termtest.Options{})
+//	s.Run(func() { choice, _ = input.GetSelection("Pick one", items) })
+//	s.Expect("Pick one")
+//	s.Press("down", "enter")
+//	s.Wait()
+type Session struct {
+	t    testing.TB
+	opts Options
+	term *terminal
+
+	mu       sync.Mutex
+	screen   *screen
+	output   bytes.Buffer
+	panic    string
+	attached bool
+
+	readDone chan struct{}
+	flowDone chan struct{}
+	closed   bool
+}
+
+// New opens a pseudo-terminal and registers its cleanup with t.
+func New(t testing.TB, opts Options) *Session {
+	t.Helper()
+
+	if opts.Width <= 0 {
+		opts.Width = 80
+	}
+	if opts.Height <= 0 {
+		opts.Height = 24
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	if opts.KeyDelay <= 0 {
+		opts.KeyDelay = 20 * time.Millisecond
+	}
+
+	sessionLock.Lock()
+
+	term, err := openTerminal(opts.Width, opts.Height)
+	if err != nil {
+		sessionLock.Unlock()
+		t.Fatalf("termtest: could not open a terminal: %s", err)
+	}
+
+	s := &Session{
+		t:        t,
+		opts:     opts,
+		term:     term,
+		screen:   newScreen(opts.Width, opts.Height),
+		readDone: make(chan struct{}),
+	}
+
+	go s.read()
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *Session) read() {
+	defer close(s.readDone)
+
+	buf := make([]byte, 4096)
+	for {
+		n, err := s.term.master.Read(buf)
+		if n > 0 {
+			s.mu.Lock()
+			s.output.Write(buf[:n])
+			reply := s.screen.feed(buf[:n])
+			s.mu.Unlock()
+
+			if len(reply) > 0 {
+				s.term.master.Write(reply)
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Run connects the terminal and starts flow in the background. Only one flow
+// may run at a time.
+func (s *Session) Run(flow func()) {
+	s.t.Helper()
+
+	if s.running() {
+		s.fatalf("termtest: a flow is already running")
+	}
+
+	if err := s.term.attach(); err != nil {
+		s.t.Fatalf("termtest: could not attach the terminal: %s", err)
+	}
+	tty.ResetStdin()
+
+	s.mu.Lock()
+	s.attached = true
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	s.flowDone = done
+
+	go func() {
+		defer close(done)
+		defer s.detach()
+		defer func() {
+			if r := recover(); r != nil {
+				s.mu.Lock()
+				s.panic = fmt.Sprintf("%v\n%s", r, debug.Stack())
+				s.mu.Unlock()
+			}
+		}()
+
+		flow()
+	}()
+}
+
+func (s *Session) detach() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.attached {
+		return
+	}
+	s.attached = false
+
+	if err := s.term.detach(); err != nil {
+		s.t.Errorf("termtest: could not restore stdin and stdout: %s", err)
+	}
+	tty.ResetStdin()
+}
+
+func (s *Session) running() bool {
+	if s.flowDone == nil {
+		return false
+	}
+
+	select {
+	case <-s.flowDone:
+		return false
+	default:
+		return true
+	}
+}
+
+// Wait blocks until the running flow returns.
+func (s *Session) Wait() {
+	s.t.Helper()
+
+	if s.flowDone == nil {
+		return
+	}
+
+	select {
+	case <-s.flowDone:
+	case <-time.After(s.opts.Timeout):
+		s.fatalf("termtest: flow did not finish within %s\n%s", s.opts.Timeout, s.frame())
+	}
+	s.settle()
+
+	s.mu.Lock()
+	panicked := s.panic
+	s.mu.Unlock()
+
+	if panicked != "" {
+		s.t.Fatalf("termtest: flow panicked: %s", panicked)
+	}
+}
+
+// settle waits until the terminal has been quiet for a moment, so that output
+// written just before a flow returned is on the screen.
+func (s *Session) settle() {
+	const quiet = 50 * time.Millisecond
+
+	deadline := time.Now().Add(s.opts.Timeout)
+	last := -1
+	for time.Now().Before(deadline) {
+		s.mu.Lock()
+		size := s.output.Len()
+		s.mu.Unlock()
+
+		if size == last {
+			return
+		}
+		last = size
+		time.Sleep(quiet)
+	}
+}
+
+// Press sends named keys: "enter", "tab", "backspace", "esc", "space", "up",
+// "down", "left", "right", "home", "end", "delete", "pgup", "pgdown" and
+// "ctrl+a" to "ctrl+z".
+func (s *Session) Press(keys ...string) {
+	s.t.Helper()
+
+	for _, key := range keys {
+		seq, ok := keySequence(key)
+		if !ok {
+			s.fatalf("termtest: unknown key %q", key)
+		}
+		s.send(seq)
+	}
+}
+
+// Type sends text as if it had been typed.
+func (s *Session) Type(text string) {
+	s.t.Helper()
+	s.send(text)
+}
+
+func (s *Session) send(seq string) {
+	s.t.Helper()
+
+	if _, err := s.term.master.Write([]byte(seq)); err != nil {
+		s.fatalf("termtest: could not write to the terminal: %s", err)
+	}
+	time.Sleep(s.opts.KeyDelay)
+}
+
+// Expect waits until text is visible on the screen.
+func (s *Session) Expect(text string) {
+	s.t.Helper()
+	s.waitFor(fmt.Sprintf("%q to appear", text), func(screen string) bool {
+		return strings.Contains(screen, text)
+	})
+}
+
+// ExpectMatch waits until the screen matches the regular expression.
+func (s *Session) ExpectMatch(pattern string) {
+	s.t.Helper()
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		s.fatalf("termtest: %s", err)
+	}
+
+	s.waitFor(fmt.Sprintf("a match for %q", pattern), re.MatchString)
+}
+
+// ExpectGone waits until text is no longer visible, e.g. after a prompt has
+// cleared itself.
+func (s *Session) ExpectGone(text string) {
+	s.t.Helper()
+	s.waitFor(fmt.Sprintf("%q to disappear", text), func(screen string) bool {
+		return !strings.Contains(screen, text)
+	})
+}
+
+func (s *Session) waitFor(what string, check func(screen string) bool) {
+	s.t.Helper()
+
+	deadline := time.Now().Add(s.opts.Timeout)
+	for {
+		if check(s.Screen()) {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			s.fatalf("termtest: timed out after %s waiting for %s\n%s", s.opts.Timeout, what, s.frame())
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Screen returns the visible screen with trailing blanks removed.
+func (s *Session) Screen() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.screen.String()
+}
+
+// Lines returns every row of the screen, including blank ones.
+func (s *Session) Lines() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.screen.lines()
+}
+
+// Output returns everything written to the terminal, escape sequences
+// included.
+func (s *Session) Output() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.output.String()
+}
+
+func (s *Session) frame() string {
+	b := &strings.Builder{}
+	b.WriteString("screen:\n")
+	for _, line := range strings.Split(s.Screen(), "\n") {
+		fmt.Fprintf(b, "| %s\n", line)
+	}
+	return b.String()
+}
+
+// fatalf stops the flow and restores stdin and stdout before failing, so
+// that the message is not written to the terminal.
+func (s *Session) fatalf(format string, args ...any) {
+	s.t.Helper()
+	s.stop()
+	s.t.Fatalf(format, args...)
+}
+
+// stop interrupts a running flow with ctrl+c, or ctrl+d for prompts reading
+// whole lines, and reports whether it returned in time. The terminal is
+// detached either way.
+func (s *Session) stop() bool {
+	stopped := true
+
+	if s.running() {
+		s.term.master.Write([]byte("\x03"))
+
+		select {
+		case <-s.flowDone:
+		case <-time.After(100 * time.Millisecond):
+			s.term.master.Write([]byte("\x04"))
+
+			select {
+			case <-s.flowDone:
+			case <-time.After(s.opts.Timeout):
+				stopped = false
+			}
+		}
+	}
+
+	s.detach()
+	return stopped
+}
+
+// Close interrupts a flow that is still running and closes the terminal. It
+// is called automatically when the test ends.
+func (s *Session) Close() {
+	s.t.Helper()
+
+	if s.closed {
+		return
+	}
+	s.closed = true
+
+	if !s.stop() {
+		s.t.Errorf("termtest: flow was still running when the session closed\n%s", s.frame())
+	}
+
+	if err := s.term.close(); err != nil {
+		s.t.Errorf("termtest: could not close the terminal: %s", err)
+	}
+	<-s.readDone
+
+	sessionLock.Unlock()
+}
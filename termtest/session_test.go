@@ -0,0 +1,70 @@
+//go:build !windows
+
+package termtest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/lspaccatrosi16/go-cli-tools/internal/tty"
+)
+
+func TestSessionLine(t *testing.T) {
+	s := New(t, Options{})
+
+	var line string
+	var err error
+	s.Run(func() {
+		fmt.Print("Name? ")
+		line, err = tty.Stdin().ReadString('\n')
+		fmt.Printf("Hello %s", line)
+	})
+
+	s.Expect("Name?")
+	s.Type("ada")
+	s.Press("enter")
+	s.Wait()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(line) != "ada" {
+		t.Errorf("read %q", line)
+	}
+	s.ExpectMatch(`Hello ada\s*$`)
+}
+
+func TestSessionInterrupt(t *testing.T) {
+	s := New(t, Options{})
+
+	s.Run(func() {
+		fmt.Print("waiting")
+		tty.Stdin().ReadString('\n')
+	})
+
+	s.Expect("waiting")
+	if !s.stop() {
+		t.Fatal("flow was not stopped")
+	}
+	if s.running() {
+		t.Error("flow still running")
+	}
+}
+
+func TestSessionSequentialFlows(t *testing.T) {
+	s := New(t, Options{})
+
+	s.Run(func() {
+		fmt.Print("first")
+	})
+	s.Wait()
+
+	s.Run(func() {
+		fmt.Print("\x1b[2J\x1b[Hsecond")
+	})
+	s.Wait()
+
+	s.Expect("second")
+	s.ExpectGone("first")
+}